@@ -0,0 +1,138 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Run modes of actions
+const (
+	ActionDetached   = "detached"   // started in the background, workman keeps running
+	ActionForeground = "foreground" // takes over the terminal until it exits
+	ActionCaptured   = "captured"   // runs in the background, its output is shown when it finishes
+)
+
+// Action is a named command run on worktrees, picked on Enter or bound to its own key
+type Action struct {
+	Name    string `mapstructure:"name"`
+	Key     string `mapstructure:"key"`     // Optional key binding in the main view
+	Script  string `mapstructure:"script"`  // Path to a script file
+	Command string `mapstructure:"command"` // Inline shell command, instead of a script file
+	Mode    string `mapstructure:"mode"`    // "detached" (default), "foreground" or "captured"
+}
+
+// RunMode returns the mode of the action, defaulting to detached
+func (a Action) RunMode() string {
+	if a.Mode == "" {
+		return ActionDetached
+	}
+	return a.Mode
+}
+
+// Text returns the script path or the command of the action
+func (a Action) Text() string {
+	if a.Script != "" {
+		return a.Script
+	}
+	return a.Command
+}
+
+// removes reports whether a repository action only removes the global action of the same name
+func (a Action) removes() bool {
+	return a.Script == "" && a.Command == ""
+}
+
+// ActionsFor returns the actions available for a repository: the global actions with those
+// of the repository replacing actions of the same name. A repository action without script
+// and command removes the global action
+func (c *Config) ActionsFor(repo *Repository) []Action {
+	actions := make([]Action, 0, len(c.Actions))
+	overrides := map[string]Action{}
+	if repo != nil {
+		for _, a := range repo.Actions {
+			overrides[a.Name] = a
+		}
+	}
+
+	for _, a := range c.Actions {
+		if override, ok := overrides[a.Name]; ok {
+			delete(overrides, a.Name)
+			if override.removes() {
+				continue
+			}
+			a = override
+		}
+		actions = append(actions, a)
+	}
+
+	// Actions only defined for the repository follow in their order
+	if repo != nil {
+		for _, a := range repo.Actions {
+			if _, ok := overrides[a.Name]; ok && !a.removes() {
+				actions = append(actions, a)
+			}
+		}
+	}
+	return actions
+}
+
+// validateActions checks the actions of the config and of each repository
+func validateActions(cfg *Config) error {
+	if err := checkActions(cfg.Actions, false); err != nil {
+		return err
+	}
+	for _, repo := range cfg.Repositories {
+		if err := checkActions(repo.Actions, true); err != nil {
+			return fmt.Errorf("repository '%s': %w", repo.Name, err)
+		}
+	}
+	return nil
+}
+
+func checkActions(actions []Action, override bool) error {
+	names := map[string]bool{}
+	for _, a := range actions {
+		if strings.TrimSpace(a.Name) == "" {
+			return fmt.Errorf("action without name")
+		}
+		if names[a.Name] {
+			return fmt.Errorf("action '%s' defined twice", a.Name)
+		}
+		names[a.Name] = true
+
+		if a.Script != "" && a.Command != "" {
+			return fmt.Errorf("action '%s' has both a script and a command", a.Name)
+		}
+		if a.removes() && !override {
+			return fmt.Errorf("action '%s' needs a script or a command", a.Name)
+		}
+		switch a.RunMode() {
+		case ActionDetached, ActionForeground, ActionCaptured:
+		default:
+			return fmt.Errorf("action '%s' has unknown mode '%s' (use detached, foreground or captured)", a.Name, a.Mode)
+		}
+	}
+	return nil
+}
+
+// actionsToMaps converts actions to maps with snake_case keys, like repositoriesToMaps
+func actionsToMaps(actions []Action) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(actions))
+	for i, a := range actions {
+		m := map[string]interface{}{"name": a.Name}
+		if a.Key != "" {
+			m["key"] = a.Key
+		}
+		if a.Script != "" {
+			m["script"] = a.Script
+		}
+		if a.Command != "" {
+			m["command"] = a.Command
+		}
+		if a.Mode != "" {
+			m["mode"] = a.Mode
+		}
+		result[i] = m
+	}
+	return result
+}
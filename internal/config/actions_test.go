@@ -0,0 +1,124 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func actionNames(actions []Action) string {
+	names := make([]string, len(actions))
+	for i, a := range actions {
+		names[i] = a.Name
+	}
+	return strings.Join(names, ",")
+}
+
+func TestActionsFor(t *testing.T) {
+	cfg := &Config{Actions: []Action{
+		{Name: "tmux", Command: "tmux split-window -c ${worktree_path}"},
+		{Name: "editor", Command: "code ${worktree_path}"},
+		{Name: "tests", Command: "make test", Mode: ActionCaptured},
+	}}
+	repo := &Repository{Name: "repo", Actions: []Action{
+		{Name: "tests", Command: "go test ./...", Mode: ActionCaptured},
+		{Name: "editor"},
+		{Name: "lint", Command: "golangci-lint run"},
+	}}
+
+	if got := actionNames(cfg.ActionsFor(nil)); got != "tmux,editor,tests" {
+		t.Errorf("global actions = %s", got)
+	}
+
+	actions := cfg.ActionsFor(repo)
+	if got := actionNames(actions); got != "tmux,tests,lint" {
+		t.Fatalf("repository actions = %s, want tmux,tests,lint", got)
+	}
+	if actions[1].Command != "go test ./..." {
+		t.Errorf("override not applied: %q", actions[1].Command)
+	}
+	if actions[0].RunMode() != ActionDetached {
+		t.Errorf("default mode = %s", actions[0].RunMode())
+	}
+}
+
+func TestValidateActions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{name: "valid", cfg: Config{Actions: []Action{{Name: "a", Script: "~/a.sh"}, {Name: "b", Command: "ls", Mode: ActionForeground}}}},
+		{name: "no name", cfg: Config{Actions: []Action{{Command: "ls"}}}, wantErr: "action without name"},
+		{name: "duplicate", cfg: Config{Actions: []Action{{Name: "a", Command: "ls"}, {Name: "a", Command: "ls"}}}, wantErr: "defined twice"},
+		{name: "script and command", cfg: Config{Actions: []Action{{Name: "a", Script: "a.sh", Command: "ls"}}}, wantErr: "both a script and a command"},
+		{name: "nothing to run", cfg: Config{Actions: []Action{{Name: "a"}}}, wantErr: "needs a script or a command"},
+		{name: "unknown mode", cfg: Config{Actions: []Action{{Name: "a", Command: "ls", Mode: "later"}}}, wantErr: "unknown mode 'later'"},
+		{name: "repository removes", cfg: Config{Repositories: []Repository{{Name: "r", Actions: []Action{{Name: "a"}}}}}},
+		{name: "repository invalid", cfg: Config{Repositories: []Repository{{Name: "r", Actions: []Action{{Name: "a", Command: "ls", Mode: "x"}}}}}, wantErr: "repository 'r'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateActions(&tt.cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSave_ActionsPersisted(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "config.toml")
+
+	viper.Reset()
+	viper.SetConfigFile(configFile)
+	viper.SetConfigType("toml")
+
+	cfg := &Config{
+		Actions: []Action{{Name: "tests", Key: "T", Command: "make test", Mode: ActionCaptured}},
+		Repositories: []Repository{
+			{Name: "repo", Path: "/test/path", Type: "local", Actions: []Action{{Name: "tests", Command: "go test ./..."}}},
+		},
+	}
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	content, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+	if !strings.Contains(string(content), "[[actions]]") {
+		t.Errorf("actions not written as array of tables:\n%s", content)
+	}
+
+	viper.Reset()
+	viper.SetConfigFile(configFile)
+	viper.SetConfigType("toml")
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	var loaded Config
+	if err := viper.Unmarshal(&loaded); err != nil {
+		t.Fatalf("Failed to unmarshal config: %v", err)
+	}
+
+	if len(loaded.Actions) != 1 || loaded.Actions[0] != cfg.Actions[0] {
+		t.Errorf("global actions = %+v", loaded.Actions)
+	}
+	if len(loaded.Repositories) != 1 || len(loaded.Repositories[0].Actions) != 1 ||
+		loaded.Repositories[0].Actions[0].Command != "go test ./..." {
+		t.Errorf("repository actions = %+v", loaded.Repositories)
+	}
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,27 +10,60 @@ import (
 )
 
 type Repository struct {
-	Name             string `mapstructure:"name"`
-	Path             string `mapstructure:"path"`
-	Type             string `mapstructure:"type"`               // "remote" or "local"
-	URL              string `mapstructure:"url"`                // For remote repos
-	PostCreateScript string `mapstructure:"post_create_script"` // Script to run after creating worktrees
+	Name             string   `mapstructure:"name"`
+	Path             string   `mapstructure:"path"`
+	Type             string   `mapstructure:"type"`               // "remote" or "local"
+	URL              string   `mapstructure:"url"`                // For remote repos
+	PostCreateScript string   `mapstructure:"post_create_script"` // Script to run after creating worktrees
+	InitSubmodules   bool     `mapstructure:"init_submodules"`    // Run "git submodule update --init --recursive" for new worktrees
+	FetchLFS         bool     `mapstructure:"fetch_lfs"`          // Run "git lfs pull" for new worktrees
+	BaseBranch       string   `mapstructure:"base_branch"`        // Branch new branches are based on and compared to
+	SyncStrategy     string   `mapstructure:"sync_strategy"`      // "rebase" (default) or "merge" when syncing with the base branch
+	Actions          []Action `mapstructure:"actions"`            // Actions replacing or adding to the global actions for this repository
 }
 
 type Config struct {
-	RootDirectory string       `mapstructure:"root_directory"`
-	Repositories  []Repository `mapstructure:"repositories"`
-	YankTemplate  string       `mapstructure:"yank_template"`
-	EnterScript   string       `mapstructure:"enter_script"` // Path to script file to execute on Enter
+	RootDirectory        string              `mapstructure:"root_directory"`
+	Repositories         []Repository        `mapstructure:"repositories"`
+	YankTemplate         string              `mapstructure:"yank_template"`
+	WorktreePathTemplate string              `mapstructure:"worktree_path_template"` // Template of the path of new worktrees
+	EnterScript          string              `mapstructure:"enter_script"`           // Path to script file to execute on Enter
+	PostCreateTimeout    int                 `mapstructure:"post_create_timeout"`    // Seconds after which post-create scripts are killed (0 disables)
+	Actions              []Action            `mapstructure:"actions"`                // Named actions picked on Enter or bound to keys
+	ScriptSubstitution   bool                `mapstructure:"script_substitution"`    // Substitute ${...} variables into script text (legacy) instead of only passing WORKMAN_* variables
+	EditorCommand        string              `mapstructure:"editor_command"`         // Command opening a worktree in an editor; $VISUAL or $EDITOR if empty
+	EditorMode           string              `mapstructure:"editor_mode"`            // "auto" (default), "gui" or "terminal"
+	StaleDays            int                 `mapstructure:"stale_days"`             // Days without commits after which branches are offered for cleanup (0 disables)
+	Watch                bool                `mapstructure:"watch"`                  // Refresh automatically when repositories or worktrees change on disk
+	WatchMaxDirs         int                 `mapstructure:"watch_max_dirs"`         // Maximum number of directories watched per repository
+	Mouse                bool                `mapstructure:"mouse"`                  // Select, open and scroll with the mouse
+	Layout               string              `mapstructure:"layout"`                 // "auto" (default), "horizontal" or "vertical" arrangement of the panes
+	Split                int                 `mapstructure:"split"`                  // Percentage of the screen taken by the repositories pane
+	HideRepos            bool                `mapstructure:"hide_repos"`             // Collapse the repositories pane to focus on the worktrees
+	Keys                 map[string][]string `mapstructure:"keys"`                   // Key binding overrides by action name
+	Theme                map[string]string   `mapstructure:"theme"`                  // Theme name and color overrides
 }
 
+// DefaultWorktreePath places worktrees in the root directory, named <reponame>-<branchname>
+const DefaultWorktreePath = "${root_dir}/${repo_name | slug}-${branch_name | slug}"
+
 func DefaultConfig() *Config {
 	homeDir, _ := os.UserHomeDir()
 	return &Config{
-		RootDirectory: filepath.Join(homeDir, "workspace"),
-		Repositories:  []Repository{},
-		YankTemplate:  "${worktree_path}",
-		EnterScript:   "",
+		RootDirectory:        filepath.Join(homeDir, "workspace"),
+		Repositories:         []Repository{},
+		YankTemplate:         "${worktree_path}",
+		WorktreePathTemplate: DefaultWorktreePath,
+		EnterScript:          "",
+		PostCreateTimeout:    600,
+		EditorCommand:        "",
+		EditorMode:           "auto",
+		StaleDays:            30,
+		Watch:                true,
+		WatchMaxDirs:         4000,
+		Mouse:                true,
+		Layout:               "auto",
+		Split:                40,
 	}
 }
 
@@ -55,7 +89,19 @@ func Load() (*Config, error) {
 	viper.SetDefault("root_directory", defaultCfg.RootDirectory)
 	viper.SetDefault("repositories", defaultCfg.Repositories)
 	viper.SetDefault("yank_template", defaultCfg.YankTemplate)
+	viper.SetDefault("worktree_path_template", defaultCfg.WorktreePathTemplate)
 	viper.SetDefault("enter_script", defaultCfg.EnterScript)
+	viper.SetDefault("post_create_timeout", defaultCfg.PostCreateTimeout)
+	viper.SetDefault("script_substitution", defaultCfg.ScriptSubstitution)
+	viper.SetDefault("editor_command", defaultCfg.EditorCommand)
+	viper.SetDefault("editor_mode", defaultCfg.EditorMode)
+	viper.SetDefault("stale_days", defaultCfg.StaleDays)
+	viper.SetDefault("watch", defaultCfg.Watch)
+	viper.SetDefault("watch_max_dirs", defaultCfg.WatchMaxDirs)
+	viper.SetDefault("mouse", defaultCfg.Mouse)
+	viper.SetDefault("layout", defaultCfg.Layout)
+	viper.SetDefault("split", defaultCfg.Split)
+	viper.SetDefault("hide_repos", defaultCfg.HideRepos)
 
 	// If config file doesn't exist, create it with defaults
 	if _, err := os.Stat(configFile); os.IsNotExist(err) {
@@ -73,6 +119,12 @@ func Load() (*Config, error) {
 	if err := viper.Unmarshal(&config); err != nil {
 		return nil, err
 	}
+	if err := validateActions(&config); err != nil {
+		return nil, fmt.Errorf("invalid [[actions]]: %w", err)
+	}
+	if err := validateTemplates(&config); err != nil {
+		return nil, err
+	}
 
 	for i := range config.Repositories {
 		script := strings.TrimSpace(config.Repositories[i].PostCreateScript)
@@ -93,7 +145,28 @@ func Save(cfg *Config) error {
 	viper.Set("root_directory", cfg.RootDirectory)
 	viper.Set("repositories", repositoriesToMaps(cfg.Repositories))
 	viper.Set("yank_template", cfg.YankTemplate)
+	viper.Set("worktree_path_template", cfg.WorktreePathTemplate)
 	viper.Set("enter_script", cfg.EnterScript)
+	viper.Set("post_create_timeout", cfg.PostCreateTimeout)
+	if len(cfg.Actions) > 0 {
+		viper.Set("actions", actionsToMaps(cfg.Actions))
+	}
+	viper.Set("script_substitution", cfg.ScriptSubstitution)
+	viper.Set("editor_command", cfg.EditorCommand)
+	viper.Set("editor_mode", cfg.EditorMode)
+	viper.Set("stale_days", cfg.StaleDays)
+	viper.Set("watch", cfg.Watch)
+	viper.Set("watch_max_dirs", cfg.WatchMaxDirs)
+	viper.Set("mouse", cfg.Mouse)
+	viper.Set("layout", cfg.Layout)
+	viper.Set("split", cfg.Split)
+	viper.Set("hide_repos", cfg.HideRepos)
+	if len(cfg.Keys) > 0 {
+		viper.Set("keys", cfg.Keys)
+	}
+	if len(cfg.Theme) > 0 {
+		viper.Set("theme", cfg.Theme)
+	}
 	return viper.WriteConfig()
 }
 
@@ -105,10 +178,17 @@ func repositoriesToMaps(repos []Repository) []map[string]interface{} {
 	result := make([]map[string]interface{}, len(repos))
 	for i, repo := range repos {
 		result[i] = map[string]interface{}{
-			"name": repo.Name,
-			"path": repo.Path,
-			"type": repo.Type,
-			"url":  repo.URL,
+			"name":            repo.Name,
+			"path":            repo.Path,
+			"type":            repo.Type,
+			"url":             repo.URL,
+			"init_submodules": repo.InitSubmodules,
+			"fetch_lfs":       repo.FetchLFS,
+			"base_branch":     repo.BaseBranch,
+			"sync_strategy":   repo.SyncStrategy,
+		}
+		if len(repo.Actions) > 0 {
+			result[i]["actions"] = actionsToMaps(repo.Actions)
 		}
 	}
 	return result
@@ -24,10 +24,12 @@ func TestSave_RepositoryFieldsPersisted(t *testing.T) {
 		YankTemplate:  "${worktree_path}",
 		Repositories: []Repository{
 			{
-				Name: "test-repo",
-				Path: "/test/path",
-				Type: "local",
-				URL:  "",
+				Name:           "test-repo",
+				Path:           "/test/path",
+				Type:           "local",
+				URL:            "",
+				InitSubmodules: true,
+				FetchLFS:       true,
 			},
 		},
 	}
@@ -84,6 +86,12 @@ func TestSave_RepositoryFieldsPersisted(t *testing.T) {
 	if loaded.Repositories[0].Type != "local" {
 		t.Errorf("Type not persisted correctly: %s", loaded.Repositories[0].Type)
 	}
+	if !loaded.Repositories[0].InitSubmodules {
+		t.Errorf("InitSubmodules not persisted correctly")
+	}
+	if !loaded.Repositories[0].FetchLFS {
+		t.Errorf("FetchLFS not persisted correctly")
+	}
 
 }
 
@@ -158,3 +166,35 @@ func TestWorktreeNotesStorage(t *testing.T) {
 		t.Errorf("Expected notes to be deleted, got %q", loaded)
 	}
 }
+
+func TestLoad_KeysTable(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "config.toml")
+	content := "[keys]\nadd = [\"a\", \"+\"]\nyank = \"Y\"\nlock = []\n"
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	viper.Reset()
+	viper.SetConfigFile(configFile)
+	viper.SetConfigType("toml")
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+
+	var loaded Config
+	if err := viper.Unmarshal(&loaded); err != nil {
+		t.Fatalf("Failed to unmarshal config: %v", err)
+	}
+
+	if got := loaded.Keys["add"]; len(got) != 2 || got[0] != "a" || got[1] != "+" {
+		t.Errorf("add = %v, want [a +]", got)
+	}
+	// A single key may be given as a string
+	if got := loaded.Keys["yank"]; len(got) != 1 || got[0] != "Y" {
+		t.Errorf("yank = %v, want [Y]", got)
+	}
+	if got, ok := loaded.Keys["lock"]; !ok || len(got) != 0 {
+		t.Errorf("lock = %v (present: %v), want empty list", got, ok)
+	}
+}
@@ -10,6 +10,7 @@ import (
 const (
 	notesDirName   = "notes"
 	scriptsDirName = "scripts"
+	runsDirName    = "runs"
 	nameSeparator  = "__"
 )
 
@@ -23,6 +24,15 @@ func ConfigDir() (string, error) {
 	return filepath.Join(homeDir, ".config", "workman"), nil
 }
 
+// RunsDir returns the directory holding the logs of scripts run by workman
+func RunsDir() (string, error) {
+	configDir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, runsDirName), nil
+}
+
 func sanitizeStorageName(name string) string {
 	name = strings.TrimSpace(name)
 	name = storageNameCleaner.ReplaceAllString(name, "-")
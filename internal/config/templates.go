@@ -0,0 +1,41 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/michael-rose/workman/internal/template"
+)
+
+// worktreePathVariables are the variables known before a worktree is created
+var worktreePathVariables = []string{"root_dir", "repo_name", "repo_path", "repo_url", "branch_name"}
+
+// validateTemplates checks the templates of the config for unknown variables and filters
+func validateTemplates(cfg *Config) error {
+	if _, err := template.Parse(cfg.YankTemplate); err != nil {
+		return fmt.Errorf("invalid yank_template: %w", err)
+	}
+	if _, err := template.Parse(cfg.EditorCommand); err != nil {
+		return fmt.Errorf("invalid editor_command: %w", err)
+	}
+
+	text := cfg.WorktreePathTemplate
+	if text == "" {
+		text = DefaultWorktreePath
+	}
+	t, err := template.Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid worktree_path_template: %w", err)
+	}
+	for _, variable := range t.Variables() {
+		if !slices.Contains(worktreePathVariables, variable) {
+			return fmt.Errorf("invalid worktree_path_template: variable '%s' is not known before the worktree is created (available: %s)",
+				variable, strings.Join(worktreePathVariables, ", "))
+		}
+	}
+	if !t.Uses("branch_name") {
+		return fmt.Errorf("invalid worktree_path_template: must contain ${branch_name} to give each worktree its own path")
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateTemplates(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{name: "defaults", cfg: *DefaultConfig()},
+		{name: "filters", cfg: Config{YankTemplate: `cd ${worktree_path | quote} # ${notes | default:"-"}`, EditorCommand: "code ${path}",
+			WorktreePathTemplate: "${root_dir}/${repo_name}/${branch_name | slug}"}},
+		{name: "unknown yank variable", cfg: Config{YankTemplate: "${worktree}"}, wantErr: "invalid yank_template: unknown variable 'worktree'"},
+		{name: "unknown editor filter", cfg: Config{EditorCommand: "code ${path | dirname}"}, wantErr: "invalid editor_command: unknown filter 'dirname'"},
+		{name: "path before creation", cfg: Config{WorktreePathTemplate: "${worktree_path}/${branch_name}"}, wantErr: "not known before the worktree is created"},
+		{name: "path without branch", cfg: Config{WorktreePathTemplate: "${root_dir}/${repo_name}"}, wantErr: "must contain ${branch_name}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTemplates(&tt.cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
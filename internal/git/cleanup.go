@@ -0,0 +1,187 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/michael-rose/workman/internal/state"
+)
+
+// Reasons a branch is suggested for cleanup
+const (
+	CleanupMerged = "merged"
+	CleanupGone   = "gone"
+	CleanupStale  = "stale"
+)
+
+// CleanupCandidate is a local branch, possibly checked out in a worktree, that is likely safe to remove
+type CleanupCandidate struct {
+	Branch       string
+	WorktreeName string // Empty if the branch is not checked out in a worktree
+	WorktreePath string
+	LastCommit   time.Time
+	Reasons      []string
+	Dirty        bool // The worktree has uncommitted changes or untracked files and is never removed
+}
+
+// branchRef is a local branch as reported by git for-each-ref
+type branchRef struct {
+	name       string
+	sha        string
+	gone       bool
+	lastCommit time.Time
+}
+
+// FindCleanupCandidates lists local branches that are merged into baseBranch, whose upstream is gone
+// or that have had no commits for staleDays (0 disables the check)
+// The base branch and the branch of the main worktree are never returned. Branches without commits
+// of their own, like a just created branch, are not reported as merged
+func FindCleanupCandidates(repoPath, baseBranch string, worktrees []state.Worktree, staleDays int) ([]CleanupCandidate, error) {
+	format := strings.Join([]string{"%(refname:short)", "%(objectname)", "%(upstream:track)", "%(committerdate:unix)"}, logFieldSeparator)
+	cmd := exec.Command("git", "for-each-ref", "--format="+format, "refs/heads")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+	branches := parseBranchRefs(string(output))
+
+	merged := map[string]bool{}
+	baseHistory := map[string]bool{}
+	if baseBranch != "" {
+		merged, err = mergedBranches(repoPath, baseBranch)
+		if err != nil {
+			return nil, err
+		}
+		baseHistory, err = firstParentHistory(repoPath, baseBranch)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Protect the base branch (also its local counterpart of e.g. "origin/main") and the main worktree
+	protected := map[string]bool{baseBranch: true}
+	remotes, err := listRemotes(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, remote := range remotes {
+		if branch, ok := strings.CutPrefix(baseBranch, remote+"/"); ok {
+			protected[branch] = true
+		}
+	}
+	byBranch := map[string]state.Worktree{}
+	for i, wt := range worktrees {
+		if i == 0 {
+			protected[wt.Branch] = true
+			continue
+		}
+		byBranch[wt.Branch] = wt
+	}
+
+	staleBefore := time.Now().AddDate(0, 0, -staleDays)
+	var candidates []CleanupCandidate
+	for _, branch := range branches {
+		if protected[branch.name] {
+			continue
+		}
+
+		var reasons []string
+		// A branch pointing into the history of the base has no commits of its own:
+		// it was just created (or fast-forwarded), not merged
+		if merged[branch.name] && !baseHistory[branch.sha] {
+			reasons = append(reasons, CleanupMerged)
+		}
+		if branch.gone {
+			reasons = append(reasons, CleanupGone)
+		}
+		if staleDays > 0 && branch.lastCommit.Before(staleBefore) {
+			reasons = append(reasons, CleanupStale)
+		}
+		if len(reasons) == 0 {
+			continue
+		}
+
+		candidate := CleanupCandidate{
+			Branch:     branch.name,
+			LastCommit: branch.lastCommit,
+			Reasons:    reasons,
+		}
+		if wt, ok := byBranch[branch.name]; ok {
+			candidate.WorktreeName = wt.Name
+			candidate.WorktreePath = wt.Path
+			dirty, err := HasUncommittedChanges(wt.Path)
+			candidate.Dirty = dirty || err != nil
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	// Oldest branches first
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].LastCommit.Before(candidates[j].LastCommit)
+	})
+	return candidates, nil
+}
+
+// mergedBranches returns the set of local branches fully merged into baseBranch
+func mergedBranches(repoPath, baseBranch string) (map[string]bool, error) {
+	cmd := exec.Command("git", "branch", "--format=%(refname:short)", "--merged", baseBranch)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches merged into %s: %w", baseBranch, err)
+	}
+
+	merged := map[string]bool{}
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			merged[line] = true
+		}
+	}
+	return merged, nil
+}
+
+// firstParentHistory returns the commits on the first-parent line of baseBranch: the commits
+// made on the base branch itself, without those of branches merged into it
+func firstParentHistory(repoPath, baseBranch string) (map[string]bool, error) {
+	cmd := exec.Command("git", "rev-list", "--first-parent", baseBranch)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list history of %s: %w", baseBranch, err)
+	}
+
+	history := map[string]bool{}
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			history[line] = true
+		}
+	}
+	return history, nil
+}
+
+// parseBranchRefs parses git for-each-ref output written with logFieldSeparator between branch
+// name, commit SHA, upstream tracking info (e.g. "[gone]", "[ahead 1]") and committer date
+func parseBranchRefs(output string) []branchRef {
+	var branches []branchRef
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, logFieldSeparator)
+		if len(fields) != 4 || fields[0] == "" {
+			continue
+		}
+		branch := branchRef{
+			name: fields[0],
+			sha:  fields[1],
+			gone: fields[2] == "[gone]",
+		}
+		if unix, err := strconv.ParseInt(strings.TrimSpace(fields[3]), 10, 64); err == nil {
+			branch.lastCommit = time.Unix(unix, 0)
+		}
+		branches = append(branches, branch)
+	}
+	return branches
+}
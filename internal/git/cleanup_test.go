@@ -0,0 +1,129 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/michael-rose/workman/internal/state"
+)
+
+func TestParseBranchRefs(t *testing.T) {
+	output := "main\x1faaa\x1f\x1f1769065799\n" +
+		"feature/login\x1fbbb\x1f[gone]\x1f1768000000\n" +
+		"wip\x1fccc\x1f[ahead 2]\x1f1769000000\n" +
+		"\n"
+
+	branches := parseBranchRefs(output)
+	if len(branches) != 3 {
+		t.Fatalf("Expected 3 branches, got %d", len(branches))
+	}
+
+	if branches[0].name != "main" || branches[0].sha != "aaa" || branches[0].gone {
+		t.Errorf("Unexpected first branch: %+v", branches[0])
+	}
+	if !branches[0].lastCommit.Equal(time.Unix(1769065799, 0)) {
+		t.Errorf("Unexpected date: %v", branches[0].lastCommit)
+	}
+	if branches[1].name != "feature/login" || !branches[1].gone {
+		t.Errorf("Expected feature/login to be gone: %+v", branches[1])
+	}
+	if branches[2].gone {
+		t.Errorf("Expected wip not to be gone: %+v", branches[2])
+	}
+}
+
+// runGit runs a git command in dir, failing the test on errors
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1",
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+}
+
+// initTestRepo creates a repository with one commit on main
+func initTestRepo(t *testing.T) string {
+	t.Helper()
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-q", "-b", "main")
+	runGit(t, repo, "commit", "-q", "--allow-empty", "-m", "initial")
+	return repo
+}
+
+func TestFindCleanupCandidates(t *testing.T) {
+	repo := initTestRepo(t)
+
+	// Merged with a merge commit: a cleanup candidate
+	runGit(t, repo, "checkout", "-q", "-b", "done")
+	runGit(t, repo, "commit", "-q", "--allow-empty", "-m", "feature")
+	runGit(t, repo, "checkout", "-q", "main")
+	runGit(t, repo, "merge", "-q", "--no-ff", "-m", "merge done", "done")
+
+	// Just created from main, and created from an older commit of main: no commits of their own
+	runGit(t, repo, "branch", "fresh")
+	runGit(t, repo, "branch", "old", "main~1")
+
+	// Merged, but checked out in a worktree with uncommitted changes
+	runGit(t, repo, "branch", "dirty", "done")
+	dirtyPath := filepath.Join(t.TempDir(), "dirty")
+	runGit(t, repo, "worktree", "add", "-q", dirtyPath, "dirty")
+	if err := os.WriteFile(filepath.Join(dirtyPath, "work.txt"), []byte("unsaved"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	worktrees := []state.Worktree{
+		{Name: "main", Path: repo, Branch: "main"},
+		{Name: "dirty", Path: dirtyPath, Branch: "dirty"},
+	}
+	candidates, err := FindCleanupCandidates(repo, "main", worktrees, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byBranch := map[string]CleanupCandidate{}
+	for _, c := range candidates {
+		byBranch[c.Branch] = c
+	}
+	if _, ok := byBranch["done"]; !ok {
+		t.Errorf("Expected the merged branch to be a candidate: %+v", candidates)
+	}
+	for _, branch := range []string{"fresh", "old", "main"} {
+		if c, ok := byBranch[branch]; ok {
+			t.Errorf("Expected %s not to be a candidate: %+v", branch, c)
+		}
+	}
+	if c := byBranch["dirty"]; c.WorktreePath != dirtyPath || !c.Dirty {
+		t.Errorf("Expected the worktree with changes to be marked dirty: %+v", c)
+	}
+	if c := byBranch["done"]; c.Dirty {
+		t.Errorf("Expected a branch without worktree not to be dirty: %+v", c)
+	}
+}
+
+func TestFindCleanupCandidatesLocalBaseWithSlash(t *testing.T) {
+	repo := initTestRepo(t)
+
+	// "1.0" is merged into the local base "release/1.0" but is not its counterpart on a remote
+	runGit(t, repo, "checkout", "-q", "-b", "1.0")
+	runGit(t, repo, "commit", "-q", "--allow-empty", "-m", "fix")
+	runGit(t, repo, "checkout", "-q", "-b", "release/1.0", "main")
+	runGit(t, repo, "merge", "-q", "--no-ff", "-m", "merge 1.0", "1.0")
+
+	worktrees := []state.Worktree{{Name: "main", Path: repo, Branch: "release/1.0"}}
+	candidates, err := FindCleanupCandidates(repo, "release/1.0", worktrees, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 1 || candidates[0].Branch != "1.0" {
+		t.Errorf("Expected 1.0 to be a candidate, got %+v", candidates)
+	}
+}
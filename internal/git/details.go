@@ -0,0 +1,176 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/michael-rose/workman/internal/state"
+)
+
+// logFieldSeparator separates the fields of a commit in the git log format
+const logFieldSeparator = "\x1f"
+
+// ResolveBaseBranch returns the branch new branches are based on and worktrees are compared to
+// A configured base branch takes precedence; otherwise origin/main or origin/master is used for remote
+// repos and the currently checked out branch for local repos
+func ResolveBaseBranch(repoPath, configured string, isRemote bool) (string, error) {
+	if configured = strings.TrimSpace(configured); configured != "" {
+		return configured, nil
+	}
+
+	if isRemote {
+		if remoteBranchExists(repoPath, "origin/main") {
+			return "origin/main", nil
+		}
+		return "origin/master", nil
+	}
+
+	return GetCurrentBranch(repoPath)
+}
+
+// GetWorktreeDetails collects HEAD, recent commits, upstream and base branch status of a worktree
+func GetWorktreeDetails(worktreePath, baseBranch string, commitCount int) (state.WorktreeDetails, error) {
+	var details state.WorktreeDetails
+
+	commits, err := RecentCommits(worktreePath, commitCount)
+	if err != nil {
+		return details, err
+	}
+	details.RecentCommits = commits
+	if len(commits) > 0 {
+		details.Head = commits[0]
+	}
+
+	details.Upstream = GetUpstream(worktreePath)
+	if details.Upstream != "" {
+		details.Ahead, details.Behind, _ = AheadBehind(worktreePath, "HEAD", details.Upstream)
+	}
+
+	details.BaseBranch = baseBranch
+	if baseBranch != "" {
+		details.AheadBase, details.BehindBase, _ = AheadBehind(worktreePath, "HEAD", baseBranch)
+	}
+
+	changed, err := changedFiles(worktreePath)
+	if err == nil {
+		details.Changes = len(changed)
+	}
+	details.LastModified = lastModified(worktreePath, changed)
+
+	return details, nil
+}
+
+// RecentCommits returns the last count commits reachable from HEAD
+func RecentCommits(worktreePath string, count int) ([]state.Commit, error) {
+	format := strings.Join([]string{"%h", "%s", "%cI"}, logFieldSeparator)
+	cmd := exec.Command("git", "log", "-n", strconv.Itoa(count), "--format="+format)
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit log: %w", err)
+	}
+	return parseCommitLog(string(output)), nil
+}
+
+// GetUpstream returns the upstream tracking branch of the checked out branch or an empty string
+func GetUpstream(worktreePath string) string {
+	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// HeadCommit returns the commit SHA checked out in a worktree
+func HeadCommit(worktreePath string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// AheadBehind counts the commits ref has that other doesn't (ahead) and vice versa (behind)
+func AheadBehind(worktreePath, ref, other string) (int, int, error) {
+	cmd := exec.Command("git", "rev-list", "--left-right", "--count", ref+"..."+other)
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to compare %s with %s: %w", ref, other, err)
+	}
+
+	fields := strings.Fields(string(output))
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %s", string(output))
+	}
+	ahead, _ := strconv.Atoi(fields[0])
+	behind, _ := strconv.Atoi(fields[1])
+	return ahead, behind, nil
+}
+
+// parseCommitLog parses git log output written with logFieldSeparator between hash, subject and date
+func parseCommitLog(output string) []state.Commit {
+	var commits []state.Commit
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, logFieldSeparator)
+		if len(fields) != 3 {
+			continue
+		}
+		date, _ := time.Parse(time.RFC3339, strings.TrimSpace(fields[2]))
+		commits = append(commits, state.Commit{
+			SHA:     fields[0],
+			Subject: fields[1],
+			Date:    date,
+		})
+	}
+	return commits
+}
+
+// changedFiles lists the paths with uncommitted changes (including untracked files) of a worktree
+func changedFiles(worktreePath string) ([]string, error) {
+	cmd := exec.Command("git", "status", "--porcelain", "-z")
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read status: %w", err)
+	}
+
+	var files []string
+	entries := strings.Split(string(output), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		files = append(files, entry[3:])
+		// Renames and copies are followed by the original path
+		if entry[0] == 'R' || entry[0] == 'C' {
+			i++
+		}
+	}
+	return files, nil
+}
+
+// lastModified returns the latest modification time of the changed files, falling back to the worktree directory
+func lastModified(worktreePath string, changed []string) time.Time {
+	var latest time.Time
+	if info, err := os.Stat(worktreePath); err == nil {
+		latest = info.ModTime()
+	}
+	for _, file := range changed {
+		info, err := os.Stat(filepath.Join(worktreePath, file))
+		if err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest
+}
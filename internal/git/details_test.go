@@ -0,0 +1,27 @@
+package git
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCommitLog(t *testing.T) {
+	output := "abc1234\x1fAdd feature\x1f2026-01-22T08:09:59+01:00\n" +
+		"def5678\x1fFix bug: with separator-like text\x1f2026-01-21T10:00:00Z\n"
+
+	commits := parseCommitLog(output)
+	if len(commits) != 2 {
+		t.Fatalf("Expected 2 commits, got %d", len(commits))
+	}
+
+	if commits[0].SHA != "abc1234" || commits[0].Subject != "Add feature" {
+		t.Errorf("Unexpected first commit: %+v", commits[0])
+	}
+	expectedDate := time.Date(2026, 1, 22, 7, 9, 59, 0, time.UTC)
+	if !commits[0].Date.Equal(expectedDate) {
+		t.Errorf("Expected date %v, got %v", expectedDate, commits[0].Date)
+	}
+	if commits[1].Subject != "Fix bug: with separator-like text" {
+		t.Errorf("Unexpected second subject: %q", commits[1].Subject)
+	}
+}
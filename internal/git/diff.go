@@ -0,0 +1,132 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// DiffMode selects which changes of a worktree are diffed
+type DiffMode int
+
+const (
+	DiffUnstaged DiffMode = iota // working tree vs index
+	DiffStaged                   // index vs HEAD
+	DiffBranch                   // working tree vs merge base with the base branch
+)
+
+func (d DiffMode) String() string {
+	switch d {
+	case DiffUnstaged:
+		return "Unstaged"
+	case DiffStaged:
+		return "Staged"
+	case DiffBranch:
+		return "Branch"
+	}
+	return "Unknown"
+}
+
+// FileDiff holds the patch of a single file
+type FileDiff struct {
+	Path    string
+	Patch   string
+	Added   int
+	Deleted int
+}
+
+// GetDiff returns the per-file diff of a worktree for the given mode
+// baseBranch is only used for DiffBranch
+func GetDiff(worktreePath string, mode DiffMode, baseBranch string) ([]FileDiff, error) {
+	args := []string{"diff", "--no-color", "--no-ext-diff"}
+	switch mode {
+	case DiffStaged:
+		args = append(args, "--staged")
+	case DiffBranch:
+		if baseBranch == "" {
+			return nil, fmt.Errorf("no base branch configured")
+		}
+		mergeBase, err := MergeBase(worktreePath, baseBranch, "HEAD")
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, mergeBase)
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diff: %w", err)
+	}
+
+	return parseDiff(string(output)), nil
+}
+
+// MergeBase returns the best common ancestor of two refs
+func MergeBase(repoPath, ref, other string) (string, error) {
+	cmd := exec.Command("git", "merge-base", ref, other)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find merge base of %s and %s: %w", ref, other, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// parseDiff splits unified diff output into per-file patches
+func parseDiff(output string) []FileDiff {
+	var files []FileDiff
+	var current *FileDiff
+	var patch []string
+	inHunk := false // after the first @@ of a file; "--- " and "+++ " are content there
+
+	flush := func() {
+		if current != nil {
+			current.Patch = strings.Join(patch, "\n")
+			files = append(files, *current)
+		}
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			flush()
+			current = &FileDiff{Path: diffPath(line)}
+			patch = []string{line}
+			inHunk = false
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		patch = append(patch, line)
+
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case !inHunk:
+			// File headers, not content
+		case strings.HasPrefix(line, "+"):
+			current.Added++
+		case strings.HasPrefix(line, "-"):
+			current.Deleted++
+		}
+	}
+	flush()
+
+	// Drop the trailing empty line of each patch caused by the final newline
+	for i := range files {
+		files[i].Patch = strings.TrimRight(files[i].Patch, "\n")
+	}
+
+	return files
+}
+
+// diffPath extracts the new path from a "diff --git a/<path> b/<path>" header
+func diffPath(header string) string {
+	header = strings.TrimPrefix(header, "diff --git ")
+	if idx := strings.LastIndex(header, " b/"); idx >= 0 {
+		return header[idx+3:]
+	}
+	return header
+}
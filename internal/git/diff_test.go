@@ -0,0 +1,49 @@
+package git
+
+import "testing"
+
+func TestParseDiff(t *testing.T) {
+	output := `diff --git a/main.go b/main.go
+index 81bee99..c4ae56c 100644
+--- a/main.go
++++ b/main.go
+@@ -1,2 +1,3 @@
+ package main
+-var a = 1
++var a = 2
++var b = 3
+diff --git a/schema.sql b/schema.sql
+index 1f2e3d4..5a6b7c8 100644
+--- a/schema.sql
++++ b/schema.sql
+@@ -1,2 +1 @@
+--- drop this comment
+ CREATE TABLE t (id INT);
+diff --git a/docs/old name.md b/docs/new name.md
+similarity 90%
+rename from docs/old name.md
+rename to docs/new name.md
+`
+
+	files := parseDiff(output)
+	if len(files) != 3 {
+		t.Fatalf("Expected 3 files, got %d", len(files))
+	}
+
+	if files[0].Path != "main.go" {
+		t.Errorf("Unexpected path: %q", files[0].Path)
+	}
+	if files[0].Added != 2 || files[0].Deleted != 1 {
+		t.Errorf("Expected +2 -1, got +%d -%d", files[0].Added, files[0].Deleted)
+	}
+	// A removed "-- " comment line looks like a file header but is inside the hunk
+	if files[1].Added != 0 || files[1].Deleted != 1 {
+		t.Errorf("Expected +0 -1 for schema.sql, got +%d -%d", files[1].Added, files[1].Deleted)
+	}
+	if files[2].Path != "docs/new name.md" {
+		t.Errorf("Unexpected renamed path: %q", files[2].Path)
+	}
+	if files[2].Added != 0 || files[2].Deleted != 0 {
+		t.Errorf("Expected no changes for rename, got +%d -%d", files[2].Added, files[2].Deleted)
+	}
+}
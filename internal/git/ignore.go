@@ -0,0 +1,35 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoredDirs returns the absolute paths of the directories of a worktree that are ignored as a whole,
+// like build output
+func IgnoredDirs(worktreePath string) ([]string, error) {
+	cmd := exec.Command("git", "ls-files", "-z", "--others", "--ignored", "--directory", "--exclude-standard")
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ignored files: %w", err)
+	}
+
+	var dirs []string
+	for _, entry := range strings.Split(string(output), "\x00") {
+		// Directories end with a slash, files are listed on their own
+		if dir, ok := strings.CutSuffix(entry, "/"); ok {
+			dirs = append(dirs, filepath.Join(worktreePath, filepath.FromSlash(dir)))
+		}
+	}
+	return dirs, nil
+}
+
+// IsIgnored checks if a path inside a worktree is ignored by git
+func IsIgnored(worktreePath, path string) bool {
+	cmd := exec.Command("git", "check-ignore", "--quiet", path)
+	cmd.Dir = worktreePath
+	return cmd.Run() == nil
+}
@@ -0,0 +1,40 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestIgnoredDirs(t *testing.T) {
+	repo := initTestRepo(t)
+	if err := os.WriteFile(filepath.Join(repo, ".gitignore"), []byte("target/\n*.log\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{"target/debug", "src"} {
+		if err := os.MkdirAll(filepath.Join(repo, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, file := range []string{"target/debug/app", "src/main.go", "build.log"} {
+		if err := os.WriteFile(filepath.Join(repo, file), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dirs, err := IgnoredDirs(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{filepath.Join(repo, "target")}; !slices.Equal(dirs, want) {
+		t.Errorf("Expected %v, got %v", want, dirs)
+	}
+
+	if !IsIgnored(repo, filepath.Join(repo, "target")) {
+		t.Error("Expected target to be ignored")
+	}
+	if IsIgnored(repo, filepath.Join(repo, "src")) {
+		t.Error("Expected src not to be ignored")
+	}
+}
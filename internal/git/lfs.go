@@ -0,0 +1,90 @@
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// UsesLFS checks if the repository or worktree at path tracks files with Git LFS
+func UsesLFS(path string) bool {
+	file, err := os.Open(filepath.Join(path, ".gitattributes"))
+	if err != nil {
+		return false
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, attr := range strings.Fields(line) {
+			if attr == "filter=lfs" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LFSAvailable checks if the git-lfs extension is installed
+func LFSAvailable() bool {
+	cmd := exec.Command("git", "lfs", "version")
+	return cmd.Run() == nil
+}
+
+// PullLFS downloads and checks out the LFS objects of a worktree
+func PullLFS(worktreePath string) error {
+	if !LFSAvailable() {
+		return fmt.Errorf("git-lfs is not installed")
+	}
+
+	// Make sure the smudge/clean filters are configured for this repository
+	cmd := exec.Command("git", "lfs", "install", "--local")
+	cmd.Dir = worktreePath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to install LFS hooks: %w\nOutput: %s", err, string(output))
+	}
+
+	cmd = exec.Command("git", "lfs", "pull")
+	cmd.Dir = worktreePath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to pull LFS objects: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// UnpushedLFSObjects lists the files whose LFS objects would not be on the remote after pushing branch
+func UnpushedLFSObjects(worktreePath, remote, branch string) ([]string, error) {
+	cmd := exec.Command("git", "lfs", "push", "--dry-run", remote, branch)
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check LFS objects: %w", err)
+	}
+	return parseLFSPushDryRun(string(output)), nil
+}
+
+// parseLFSPushDryRun parses the output of git lfs push --dry-run
+// Each line looks like "push <oid> => <path>"
+func parseLFSPushDryRun(output string) []string {
+	var paths []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "push ") {
+			continue
+		}
+		if _, path, found := strings.Cut(line, " => "); found {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
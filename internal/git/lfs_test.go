@@ -0,0 +1,39 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseLFSPushDryRun(t *testing.T) {
+	output := `push 5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03 => assets/logo.png
+push 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08 => models/weights bin.dat
+`
+
+	paths := parseLFSPushDryRun(output)
+	if len(paths) != 2 {
+		t.Fatalf("Expected 2 paths, got %d", len(paths))
+	}
+	if paths[0] != "assets/logo.png" {
+		t.Errorf("Unexpected path: %q", paths[0])
+	}
+	if paths[1] != "models/weights bin.dat" {
+		t.Errorf("Unexpected path: %q", paths[1])
+	}
+}
+
+func TestUsesLFS(t *testing.T) {
+	tmpDir := t.TempDir()
+	if UsesLFS(tmpDir) {
+		t.Errorf("Expected no LFS usage without .gitattributes")
+	}
+
+	attributes := "# binaries\n*.txt text\n*.psd filter=lfs diff=lfs merge=lfs -text\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ".gitattributes"), []byte(attributes), 0644); err != nil {
+		t.Fatalf("Failed to write .gitattributes: %v", err)
+	}
+	if !UsesLFS(tmpDir) {
+		t.Errorf("Expected LFS usage to be detected")
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package git
+
+import "os/exec"
+
+// killProcessGroupOnCancel leaves killing the process to exec.CommandContext where
+// process groups are not available
+func killProcessGroupOnCancel(cmd *exec.Cmd) {}
@@ -0,0 +1,63 @@
+//go:build unix
+
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestPostCreateCommandKillsProcessGroup(t *testing.T) {
+	dir := t.TempDir()
+	pidFile := filepath.Join(dir, "pid")
+	script := "sleep 30 & echo $! > " + pidFile + "; wait"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	cmd := PostCreateCommand(ctx, script, dir, dir)
+
+	start := time.Now()
+	if err := cmd.Run(); err == nil {
+		t.Fatal("Expected the cancelled script to fail")
+	}
+	if took := time.Since(start); took > 5*time.Second {
+		t.Errorf("Expected the script to be killed at the timeout, took %v", took)
+	}
+
+	content, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatalf("Failed to read pid of the background process: %v", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		t.Fatalf("Invalid pid %q: %v", content, err)
+	}
+	// The killed process may take a moment to be reaped by init
+	for i := 0; i < 50 && running(pid); i++ {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if running(pid) {
+		_ = syscall.Kill(pid, syscall.SIGKILL)
+		t.Error("Expected the process started by the script to be killed with it")
+	}
+}
+
+// running reports whether a process exists and is not a zombie waiting to be reaped
+func running(pid int) bool {
+	if syscall.Kill(pid, 0) != nil {
+		return false
+	}
+	// The state follows the parenthesized command name in /proc/<pid>/stat on Linux
+	stat, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return true
+	}
+	_, state, _ := strings.Cut(string(stat), ") ")
+	return !strings.HasPrefix(state, "Z")
+}
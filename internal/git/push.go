@@ -0,0 +1,87 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// listRemotes returns the names of the configured remotes
+func listRemotes(repoPath string) ([]string, error) {
+	cmd := exec.Command("git", "remote")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remotes: %w", err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// DefaultRemote returns "origin" if it exists, otherwise the first configured remote
+func DefaultRemote(repoPath string) (string, error) {
+	remotes, err := listRemotes(repoPath)
+	if err != nil {
+		return "", err
+	}
+	if len(remotes) == 0 {
+		return "", fmt.Errorf("repository has no remotes")
+	}
+	for _, remote := range remotes {
+		if remote == "origin" {
+			return remote, nil
+		}
+	}
+	return remotes[0], nil
+}
+
+// UpstreamRemote returns the remote of a branch's upstream, or "" if it has none on a remote
+func UpstreamRemote(worktreePath, branch string) string {
+	cmd := exec.Command("git", "config", "--get", "branch."+branch+".remote")
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	// "." is the local repository
+	if remote := strings.TrimSpace(string(output)); remote != "." {
+		return remote
+	}
+	return ""
+}
+
+// PushRemote returns the remote a branch is pushed to: the remote of its upstream, otherwise the default remote
+func PushRemote(worktreePath, branch string) (string, error) {
+	if remote := UpstreamRemote(worktreePath, branch); remote != "" {
+		return remote, nil
+	}
+	return DefaultRemote(worktreePath)
+}
+
+// UnpushedCommits counts the commits of a worktree's HEAD that are not on its upstream
+// If the branch has no upstream, commits not present on any remote are counted
+func UnpushedCommits(worktreePath string) (int, error) {
+	if upstream := GetUpstream(worktreePath); upstream != "" {
+		ahead, _, err := AheadBehind(worktreePath, "HEAD", upstream)
+		return ahead, err
+	}
+
+	cmd := exec.Command("git", "rev-list", "--count", "HEAD", "--not", "--remotes")
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unpushed commits: %w", err)
+	}
+	return strconv.Atoi(strings.TrimSpace(string(output)))
+}
+
+// Push pushes a branch and sets its upstream, returning git's output
+func Push(worktreePath, remote, branch string) (string, error) {
+	cmd := exec.Command("git", "push", "-u", remote, branch)
+	cmd.Dir = worktreePath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(output), fmt.Errorf("failed to push %s to %s: %w", branch, remote, err)
+	}
+	return string(output), nil
+}
@@ -0,0 +1,43 @@
+package git
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestPushToUpstreamRemote(t *testing.T) {
+	origin := initTestRepo(t)
+	fork := filepath.Join(t.TempDir(), "fork.git")
+	runGit(t, origin, "clone", "-q", "--bare", origin, fork)
+	clone := filepath.Join(t.TempDir(), "clone")
+	runGit(t, origin, "clone", "-q", origin, clone)
+	runGit(t, clone, "remote", "add", "fork", fork)
+	runGit(t, clone, "fetch", "-q", "fork")
+
+	// A branch tracking the fork is pushed there, a new branch has no upstream remote
+	runGit(t, clone, "checkout", "-q", "-b", "feature", "--track", "fork/main")
+	runGit(t, clone, "commit", "-q", "--allow-empty", "-m", "feature")
+	runGit(t, clone, "branch", "fresh")
+
+	if remote := UpstreamRemote(clone, "main"); remote != "origin" {
+		t.Errorf("Expected upstream remote origin for main, got %q", remote)
+	}
+	if remote := UpstreamRemote(clone, "fresh"); remote != "" {
+		t.Errorf("Expected no upstream remote for fresh, got %q", remote)
+	}
+	if remote, err := PushRemote(clone, "fresh"); err != nil || remote != "origin" {
+		t.Errorf("Expected fresh to be pushed to origin, got %q (%v)", remote, err)
+	}
+
+	remote := UpstreamRemote(clone, "feature")
+	if remote != "fork" {
+		t.Fatalf("Expected upstream remote fork for feature, got %q", remote)
+	}
+	if output, err := Push(clone, remote, "feature"); err != nil {
+		t.Fatalf("Push failed: %v\n%s", err, output)
+	}
+	if err := exec.Command("git", "-C", fork, "rev-parse", "--verify", "--quiet", "refs/heads/feature").Run(); err != nil {
+		t.Error("Expected feature to be pushed to the fork")
+	}
+}
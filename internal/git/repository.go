@@ -1,31 +1,17 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
 	"strings"
+	"time"
 
 	"github.com/michael-rose/workman/internal/state"
 )
 
-// sanitizeName converts a name to only contain alphanumeric characters and dashes
-func sanitizeName(name string) string {
-	// Replace any non-alphanumeric characters (except dash) with dash
-	reg := regexp.MustCompile(`[^a-zA-Z0-9\-]+`)
-	sanitized := reg.ReplaceAllString(name, "-")
-
-	// Remove leading/trailing dashes and collapse multiple dashes
-	sanitized = strings.Trim(sanitized, "-")
-	reg = regexp.MustCompile(`-+`)
-	sanitized = reg.ReplaceAllString(sanitized, "-")
-
-	// Convert to lowercase for consistency
-	return strings.ToLower(sanitized)
-}
-
 // ListWorktrees lists all worktrees for a given repository path
 func ListWorktrees(repoPath string) ([]state.Worktree, error) {
 	cmd := exec.Command("git", "worktree", "list", "--porcelain")
@@ -44,6 +30,7 @@ func parseWorktreeList(output string) ([]state.Worktree, error) {
 	lines := strings.Split(strings.TrimSpace(output), "\n")
 
 	var currentPath, currentBranch string
+	var currentLocked bool
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 		if line == "" {
@@ -54,9 +41,11 @@ func parseWorktreeList(output string) ([]state.Worktree, error) {
 					Name:   name,
 					Branch: currentBranch,
 					Path:   currentPath,
+					Locked: currentLocked,
 				})
 				currentPath = ""
 				currentBranch = ""
+				currentLocked = false
 			}
 			continue
 		}
@@ -67,6 +56,8 @@ func parseWorktreeList(output string) ([]state.Worktree, error) {
 			currentBranch = strings.TrimPrefix(line, "branch refs/heads/")
 		} else if strings.HasPrefix(line, "detached") {
 			currentBranch = "detached HEAD"
+		} else if line == "locked" || strings.HasPrefix(line, "locked ") {
+			currentLocked = true
 		}
 	}
 
@@ -77,6 +68,7 @@ func parseWorktreeList(output string) ([]state.Worktree, error) {
 			Name:   name,
 			Branch: currentBranch,
 			Path:   currentPath,
+			Locked: currentLocked,
 		})
 	}
 
@@ -106,24 +98,15 @@ func BranchExists(repoPath, branch string) (bool, error) {
 	return true, nil
 }
 
-// AddWorktree creates a new worktree for the repository in the root directory
-// If the branch doesn't exist, it creates it based on main/master (remote) or current branch (local)
-// Worktree will be created at: <rootDir>/<sanitizedRepoName>-<sanitizedBranchName>
-func AddWorktree(repoPath, rootDir, repoName, branch string, isRemote bool) error {
+// AddWorktree creates a new worktree for the repository at worktreePath
+// If the branch doesn't exist, it creates it based on baseBranch (see ResolveBaseBranch)
+func AddWorktree(repoPath, worktreePath, branch, baseBranch string) error {
 	// Check if branch exists
 	exists, err := BranchExists(repoPath, branch)
 	if err != nil {
 		return fmt.Errorf("failed to check if branch exists: %w", err)
 	}
 
-	// Sanitize names
-	sanitizedRepo := sanitizeName(repoName)
-	sanitizedBranch := sanitizeName(branch)
-
-	// Determine worktree path: <rootDir>/<reponame>-<branchname>
-	worktreeName := fmt.Sprintf("%s-%s", sanitizedRepo, sanitizedBranch)
-	worktreePath := filepath.Join(rootDir, worktreeName)
-
 	// Check if path already exists
 	if _, err := os.Stat(worktreePath); err == nil {
 		return fmt.Errorf("path already exists: %s", worktreePath)
@@ -134,22 +117,8 @@ func AddWorktree(repoPath, rootDir, repoName, branch string, isRemote bool) erro
 		// Branch exists, just create worktree
 		cmd = exec.Command("git", "worktree", "add", worktreePath, branch)
 	} else {
-		// Branch doesn't exist, create it
-		if isRemote {
-			// For remote repos, try to base on origin/main or origin/master
-			baseBranch := "origin/main"
-			if !remoteBranchExists(repoPath, "origin/main") {
-				baseBranch = "origin/master"
-			}
-			cmd = exec.Command("git", "worktree", "add", "-b", branch, worktreePath, baseBranch)
-		} else {
-			// For local repos, base on current branch
-			currentBranch, err := GetCurrentBranch(repoPath)
-			if err != nil {
-				return fmt.Errorf("failed to get current branch: %w", err)
-			}
-			cmd = exec.Command("git", "worktree", "add", "-b", branch, worktreePath, currentBranch)
-		}
+		// Branch doesn't exist, create it from the base branch
+		cmd = exec.Command("git", "worktree", "add", "-b", branch, worktreePath, baseBranch)
 	}
 
 	cmd.Dir = repoPath
@@ -181,6 +150,28 @@ func RemoveWorktree(repoPath, worktreePath string) error {
 	return nil
 }
 
+// LockWorktree locks a worktree so it cannot be pruned, moved or removed
+func LockWorktree(repoPath, worktreePath string) error {
+	cmd := exec.Command("git", "worktree", "lock", worktreePath)
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to lock worktree: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// UnlockWorktree unlocks a locked worktree
+func UnlockWorktree(repoPath, worktreePath string) error {
+	cmd := exec.Command("git", "worktree", "unlock", worktreePath)
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to unlock worktree: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
 // DeleteBranch deletes a branch forcefully (even if unmerged)
 func DeleteBranch(repoPath, branch string) error {
 	// Use -D (force delete) to remove even if unmerged
@@ -245,21 +236,16 @@ func ListBranches(repoPath string) ([]string, error) {
 	return branches, nil
 }
 
-// ExecutePostCreateScript executes a bash script after worktree creation
-// The script receives two arguments: repo path and worktree path
-func ExecutePostCreateScript(script, repoPath, worktreePath string) error {
-	if script == "" {
-		return nil
-	}
-
-	cmd := exec.Command("bash", "-c", script, "--", repoPath, worktreePath)
+// PostCreateCommand creates the command running a post-create script in a new worktree
+// The script receives two arguments: repo path and worktree path. It runs in its own process
+// group, which is killed as a whole when ctx is done, so that processes it started do not linger
+func PostCreateCommand(ctx context.Context, script, repoPath, worktreePath string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "bash", "-c", script, "--", repoPath, worktreePath)
 	cmd.Dir = worktreePath
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("script execution failed: %w\nOutput: %s", err, string(output))
-	}
-
-	return nil
+	killProcessGroupOnCancel(cmd)
+	// Don't wait for output of processes that escaped the group
+	cmd.WaitDelay = 2 * time.Second
+	return cmd
 }
 
 // CloneRepository clones a remote repository to the specified path
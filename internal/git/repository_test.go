@@ -0,0 +1,27 @@
+package git
+
+import "testing"
+
+func TestParseWorktreeList(t *testing.T) {
+	output := "worktree /repos/app\nHEAD abc\nbranch refs/heads/main\n\n" +
+		"worktree /repos/app-feature\nHEAD def\nbranch refs/heads/feature\nlocked\n\n" +
+		"worktree /repos/app-detached\nHEAD 123\ndetached\nlocked on external disk\n"
+
+	worktrees, err := parseWorktreeList(output)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(worktrees) != 3 {
+		t.Fatalf("Expected 3 worktrees, got %d", len(worktrees))
+	}
+
+	if worktrees[0].Name != "app" || worktrees[0].Branch != "main" || worktrees[0].Locked {
+		t.Errorf("Unexpected first worktree: %+v", worktrees[0])
+	}
+	if worktrees[1].Branch != "feature" || !worktrees[1].Locked {
+		t.Errorf("Expected feature worktree to be locked: %+v", worktrees[1])
+	}
+	if worktrees[2].Branch != "detached HEAD" || !worktrees[2].Locked {
+		t.Errorf("Expected detached worktree with lock reason to be locked: %+v", worktrees[2])
+	}
+}
@@ -0,0 +1,106 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Stash describes an entry of the stash list
+// Stashes are shared by all worktrees of a repository
+type Stash struct {
+	Ref     string // e.g. "stash@{0}"
+	Branch  string // branch the stash was created on
+	Message string
+	Date    time.Time
+}
+
+// ListStashes lists all stash entries of the repository, newest first
+func ListStashes(repoPath string) ([]Stash, error) {
+	format := strings.Join([]string{"%gd", "%gs", "%cI"}, logFieldSeparator)
+	cmd := exec.Command("git", "stash", "list", "--format="+format)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stashes: %w", err)
+	}
+	return parseStashList(string(output)), nil
+}
+
+// StashPush stashes all changes of a worktree including untracked files
+func StashPush(worktreePath, message string) error {
+	args := []string{"stash", "push", "--include-untracked"}
+	if message = strings.TrimSpace(message); message != "" {
+		args = append(args, "-m", message)
+	}
+	return runStash(worktreePath, args...)
+}
+
+// StashApply applies a stash to a worktree and keeps it in the stash list
+func StashApply(worktreePath, ref string) error {
+	return runStash(worktreePath, "stash", "apply", ref)
+}
+
+// StashPop applies a stash to a worktree and removes it from the stash list
+func StashPop(worktreePath, ref string) error {
+	return runStash(worktreePath, "stash", "pop", ref)
+}
+
+// StashDrop removes a stash from the stash list
+func StashDrop(repoPath, ref string) error {
+	return runStash(repoPath, "stash", "drop", ref)
+}
+
+// StashDiff returns the per-file diff of a stash including untracked files
+func StashDiff(repoPath, ref string) ([]FileDiff, error) {
+	cmd := exec.Command("git", "stash", "show", "-p", "--include-untracked", "--no-color", "--no-ext-diff", ref)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to show stash: %w", err)
+	}
+	return parseDiff(string(output)), nil
+}
+
+func runStash(path string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = path
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git %s failed: %w\nOutput: %s", strings.Join(args[:2], " "), err, string(output))
+	}
+	return nil
+}
+
+// parseStashList parses git stash list output written with logFieldSeparator between ref, subject and date
+// Subjects look like "WIP on <branch>: <sha> <message>" or "On <branch>: <message>"
+func parseStashList(output string) []Stash {
+	var stashes []Stash
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, logFieldSeparator)
+		if len(fields) != 3 {
+			continue
+		}
+
+		stash := Stash{Ref: fields[0], Message: fields[1]}
+		stash.Date, _ = time.Parse(time.RFC3339, strings.TrimSpace(fields[2]))
+
+		subject := fields[1]
+		wip := strings.HasPrefix(subject, "WIP on ")
+		subject = strings.TrimPrefix(strings.TrimPrefix(subject, "WIP on "), "On ")
+		if branch, message, found := strings.Cut(subject, ": "); found {
+			stash.Branch = branch
+			stash.Message = message
+			if wip {
+				// Drop the abbreviated commit hash in front of the commit subject
+				if _, rest, found := strings.Cut(message, " "); found {
+					stash.Message = "WIP: " + rest
+				}
+			}
+		}
+
+		stashes = append(stashes, stash)
+	}
+	return stashes
+}
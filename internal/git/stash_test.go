@@ -0,0 +1,23 @@
+package git
+
+import "testing"
+
+func TestParseStashList(t *testing.T) {
+	output := "stash@{0}\x1fOn feature/login: before rebase\x1f2026-01-22T08:09:59Z\n" +
+		"stash@{1}\x1fWIP on main: abc1234 Add feature\x1f2026-01-21T10:00:00Z\n"
+
+	stashes := parseStashList(output)
+	if len(stashes) != 2 {
+		t.Fatalf("Expected 2 stashes, got %d", len(stashes))
+	}
+
+	if stashes[0].Ref != "stash@{0}" || stashes[0].Branch != "feature/login" || stashes[0].Message != "before rebase" {
+		t.Errorf("Unexpected first stash: %+v", stashes[0])
+	}
+	if stashes[1].Branch != "main" || stashes[1].Message != "WIP: Add feature" {
+		t.Errorf("Unexpected second stash: %+v", stashes[1])
+	}
+	if stashes[1].Date.IsZero() {
+		t.Errorf("Expected date to be parsed")
+	}
+}
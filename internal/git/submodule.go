@@ -0,0 +1,107 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/michael-rose/workman/internal/state"
+)
+
+// HasSubmodules checks if the repository or worktree at path declares submodules
+func HasSubmodules(path string) bool {
+	info, err := os.Stat(filepath.Join(path, ".gitmodules"))
+	if err != nil {
+		return false
+	}
+	return info.Size() > 0
+}
+
+// UpdateSubmodules initializes and checks out all submodules of a worktree recursively
+func UpdateSubmodules(worktreePath string) error {
+	cmd := exec.Command("git", "submodule", "update", "--init", "--recursive")
+	cmd.Dir = worktreePath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to update submodules: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// ListSubmodules lists all submodules of a worktree including whether they have uncommitted changes
+func ListSubmodules(worktreePath string) ([]state.Submodule, error) {
+	if !HasSubmodules(worktreePath) {
+		return []state.Submodule{}, nil
+	}
+
+	cmd := exec.Command("git", "submodule", "status", "--recursive")
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list submodules: %w", err)
+	}
+
+	submodules := parseSubmoduleStatus(string(output))
+	for i, sub := range submodules {
+		if sub.Status == state.SubmoduleUninitialized {
+			continue
+		}
+		// Submodules whose status cannot be read are not reported as dirty
+		submodules[i].Dirty, _ = HasUncommittedChanges(filepath.Join(worktreePath, sub.Path))
+	}
+
+	return submodules, nil
+}
+
+// DirtySubmodules returns the paths of all submodules whose changes would be lost when removing the worktree
+func DirtySubmodules(worktreePath string) ([]string, error) {
+	submodules, err := ListSubmodules(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirty []string
+	for _, sub := range submodules {
+		if sub.Dirty || sub.Status == state.SubmoduleModified || sub.Status == state.SubmoduleConflict {
+			dirty = append(dirty, sub.Path)
+		}
+	}
+	return dirty, nil
+}
+
+// parseSubmoduleStatus parses the output of git submodule status
+// Each line looks like "<status><sha> <path> (<describe>)" where status is one of ' ', '-', '+' or 'U'
+func parseSubmoduleStatus(output string) []state.Submodule {
+	var submodules []state.Submodule
+	for _, line := range strings.Split(output, "\n") {
+		if len(strings.TrimSpace(line)) < 2 {
+			continue
+		}
+
+		var status state.SubmoduleStatus
+		switch line[0] {
+		case '-':
+			status = state.SubmoduleUninitialized
+		case '+':
+			status = state.SubmoduleModified
+		case 'U':
+			status = state.SubmoduleConflict
+		default:
+			status = state.SubmoduleClean
+		}
+
+		fields := strings.Fields(line[1:])
+		if len(fields) < 2 {
+			continue
+		}
+
+		submodules = append(submodules, state.Submodule{
+			Path:   fields[1],
+			SHA:    fields[0],
+			Status: status,
+		})
+	}
+	return submodules
+}
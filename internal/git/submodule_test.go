@@ -0,0 +1,49 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/michael-rose/workman/internal/state"
+)
+
+func TestParseSubmoduleStatus(t *testing.T) {
+	output := ` 1111111111111111111111111111111111111111 libs/clean (v1.0.0)
+-2222222222222222222222222222222222222222 libs/uninit
++3333333333333333333333333333333333333333 libs/modified (heads/main)
+U4444444444444444444444444444444444444444 libs/conflict
+`
+
+	submodules := parseSubmoduleStatus(output)
+	if len(submodules) != 4 {
+		t.Fatalf("Expected 4 submodules, got %d", len(submodules))
+	}
+
+	expected := []struct {
+		path   string
+		status state.SubmoduleStatus
+	}{
+		{"libs/clean", state.SubmoduleClean},
+		{"libs/uninit", state.SubmoduleUninitialized},
+		{"libs/modified", state.SubmoduleModified},
+		{"libs/conflict", state.SubmoduleConflict},
+	}
+
+	for i, exp := range expected {
+		if submodules[i].Path != exp.path {
+			t.Errorf("Submodule %d: expected path %q, got %q", i, exp.path, submodules[i].Path)
+		}
+		if submodules[i].Status != exp.status {
+			t.Errorf("Submodule %d: expected status %q, got %q", i, exp.status, submodules[i].Status)
+		}
+	}
+
+	if submodules[0].SHA != "1111111111111111111111111111111111111111" {
+		t.Errorf("Unexpected SHA: %s", submodules[0].SHA)
+	}
+}
+
+func TestParseSubmoduleStatus_Empty(t *testing.T) {
+	if submodules := parseSubmoduleStatus(""); len(submodules) != 0 {
+		t.Errorf("Expected no submodules, got %d", len(submodules))
+	}
+}
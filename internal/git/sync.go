@@ -0,0 +1,183 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	SyncRebase = "rebase"
+	SyncMerge  = "merge"
+)
+
+// ConflictError is returned when a rebase or merge stopped because of conflicts
+// The operation is left in progress so it can be continued or aborted
+type ConflictError struct {
+	Operation string // SyncRebase or SyncMerge
+	Files     []string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s stopped with conflicts in %d file(s)", e.Operation, len(e.Files))
+}
+
+// Fetch fetches all remotes of a repository and prunes deleted remote branches
+func Fetch(repoPath string) error {
+	cmd := exec.Command("git", "fetch", "--all", "--prune")
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to fetch: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// IsRemoteTrackingBranch checks if ref names a remote-tracking branch like "origin/main"
+func IsRemoteTrackingBranch(repoPath, ref string) bool {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", "refs/remotes/"+ref)
+	cmd.Dir = repoPath
+	return cmd.Run() == nil
+}
+
+// FetchWorktree fetches the remote of the checked out branch of a worktree and returns git's output
+func FetchWorktree(worktreePath string) (string, error) {
+	cmd := exec.Command("git", "fetch", "--prune")
+	cmd.Dir = worktreePath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(output), fmt.Errorf("failed to fetch: %w", err)
+	}
+	return string(output), nil
+}
+
+// IsClean checks if a worktree has no staged or unstaged changes to tracked files
+func IsClean(worktreePath string) (bool, error) {
+	cmd := exec.Command("git", "status", "--porcelain", "--untracked-files=no")
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to read status: %w", err)
+	}
+	return strings.TrimSpace(string(output)) == "", nil
+}
+
+// HasUncommittedChanges checks if a worktree has staged, unstaged or untracked changes
+func HasUncommittedChanges(worktreePath string) (bool, error) {
+	cmd := exec.Command("git", "status", "--porcelain")
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to read status: %w", err)
+	}
+	return strings.TrimSpace(string(output)) != "", nil
+}
+
+// SyncWithBase rebases the checked out branch of a worktree onto baseBranch or merges baseBranch into it
+// Returns a *ConflictError if the operation stopped because of conflicts
+func SyncWithBase(worktreePath, baseBranch, strategy string) error {
+	var cmd *exec.Cmd
+	switch strategy {
+	case SyncMerge:
+		cmd = exec.Command("git", "merge", "--no-edit", baseBranch)
+	default:
+		strategy = SyncRebase
+		cmd = exec.Command("git", "rebase", baseBranch)
+	}
+	cmd.Dir = worktreePath
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+
+	if files, conflictErr := ConflictedFiles(worktreePath); conflictErr == nil && len(files) > 0 {
+		return &ConflictError{Operation: strategy, Files: files}
+	}
+	return fmt.Errorf("%s onto %s failed: %w\nOutput: %s", strategy, baseBranch, err, string(output))
+}
+
+// ConflictedFiles lists the unmerged files of a worktree
+func ConflictedFiles(worktreePath string) ([]string, error) {
+	cmd := exec.Command("git", "diff", "--name-only", "--diff-filter=U")
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conflicted files: %w", err)
+	}
+
+	var files []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// SyncInProgress returns SyncRebase or SyncMerge if such an operation is in progress in the worktree, or ""
+func SyncInProgress(worktreePath string) string {
+	for _, check := range []struct {
+		path      string
+		operation string
+	}{
+		{"rebase-merge", SyncRebase},
+		{"rebase-apply", SyncRebase},
+		{"MERGE_HEAD", SyncMerge},
+	} {
+		cmd := exec.Command("git", "rev-parse", "--git-path", check.path)
+		cmd.Dir = worktreePath
+		output, err := cmd.Output()
+		if err != nil {
+			continue
+		}
+		path := strings.TrimSpace(string(output))
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(worktreePath, path)
+		}
+		if _, err := os.Stat(path); err == nil {
+			return check.operation
+		}
+	}
+	return ""
+}
+
+// AbortSync aborts a rebase or merge in progress
+func AbortSync(worktreePath, operation string) error {
+	cmd := exec.Command("git", operation, "--abort")
+	cmd.Dir = worktreePath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to abort %s: %w\nOutput: %s", operation, err, string(output))
+	}
+	return nil
+}
+
+// ContinueSync continues a rebase or merge after conflicts have been resolved and staged
+// Returns a *ConflictError if the next step stopped because of conflicts again
+func ContinueSync(worktreePath, operation string) error {
+	// Use a no-op editor so git doesn't wait for commit message input; GIT_EDITOR takes precedence over core.editor
+	cmd := exec.Command("git", operation, "--continue")
+	cmd.Dir = worktreePath
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+
+	if files, conflictErr := ConflictedFiles(worktreePath); conflictErr == nil && len(files) > 0 {
+		return &ConflictError{Operation: operation, Files: files}
+	}
+	return fmt.Errorf("failed to continue %s: %w\nOutput: %s", operation, err, string(output))
+}
+
+// IsConflict reports whether err is a *ConflictError and returns it
+func IsConflict(err error) (*ConflictError, bool) {
+	var conflictErr *ConflictError
+	if errors.As(err, &conflictErr) {
+		return conflictErr, true
+	}
+	return nil, false
+}
@@ -0,0 +1,66 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsRemoteTrackingBranch(t *testing.T) {
+	origin := initTestRepo(t)
+	clone := filepath.Join(t.TempDir(), "clone")
+	runGit(t, origin, "clone", "-q", origin, clone)
+
+	if !IsRemoteTrackingBranch(clone, "origin/main") {
+		t.Error("Expected origin/main to be a remote-tracking branch")
+	}
+	for _, ref := range []string{"main", "origin/missing"} {
+		if IsRemoteTrackingBranch(clone, ref) {
+			t.Errorf("Expected %s not to be a remote-tracking branch", ref)
+		}
+	}
+}
+
+func TestContinueSyncIgnoresEditor(t *testing.T) {
+	repo := initTestRepo(t)
+	write := func(content string) {
+		if err := os.WriteFile(filepath.Join(repo, "file.txt"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("base\n")
+	runGit(t, repo, "add", "file.txt")
+	runGit(t, repo, "commit", "-q", "-m", "base")
+	runGit(t, repo, "checkout", "-q", "-b", "feature")
+	write("feature\n")
+	runGit(t, repo, "commit", "-q", "-am", "feature")
+	runGit(t, repo, "checkout", "-q", "main")
+	write("main\n")
+	runGit(t, repo, "commit", "-q", "-am", "main")
+
+	for key, value := range map[string]string{
+		"GIT_AUTHOR_NAME":     "test",
+		"GIT_AUTHOR_EMAIL":    "test@example.com",
+		"GIT_COMMITTER_NAME":  "test",
+		"GIT_COMMITTER_EMAIL": "test@example.com",
+		"GIT_CONFIG_GLOBAL":   "/dev/null",
+		"GIT_CONFIG_NOSYSTEM": "1",
+	} {
+		t.Setenv(key, value)
+	}
+	err := SyncWithBase(repo, "feature", "merge")
+	if _, ok := IsConflict(err); !ok {
+		t.Fatalf("Expected a conflict, got %v", err)
+	}
+	write("resolved\n")
+	runGit(t, repo, "add", "file.txt")
+
+	// A configured editor would need a terminal; continuing must not start it
+	t.Setenv("GIT_EDITOR", "false")
+	if err := ContinueSync(repo, "merge"); err != nil {
+		t.Fatalf("ContinueSync failed: %v", err)
+	}
+	if operation := SyncInProgress(repo); operation != "" {
+		t.Errorf("Expected no %s in progress", operation)
+	}
+}
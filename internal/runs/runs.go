@@ -0,0 +1,319 @@
+// Package runs records the scripts and commands workman launches: their output is written
+// to a log file per run and their exit status is kept next to it
+package runs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultKeep is the number of runs kept on disk; older logs are deleted
+const DefaultKeep = 50
+
+// Statuses of a run
+const (
+	Running   = "running"
+	Succeeded = "succeeded"
+	Failed    = "failed"
+	Unknown   = "unknown" // workman exited before the run finished
+)
+
+// Run is one execution of a script or command
+type Run struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"` // what ran, e.g. "Enter script" or the name of an action
+	Repo     string    `json:"repo,omitempty"`
+	Worktree string    `json:"worktree,omitempty"`
+	Command  string    `json:"command"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+	ExitCode int       `json:"exit_code"`
+	Error    string    `json:"error,omitempty"`
+	LogPath  string    `json:"log_path,omitempty"`  // empty when the output went to the terminal
+	LogError string    `json:"log_error,omitempty"` // why no log file was written; the command ran anyway
+
+	lost bool // loaded from disk without finishing
+}
+
+// Status returns whether the run is still running, succeeded or failed
+func (r Run) Status() string {
+	switch {
+	case r.lost:
+		return Unknown
+	case r.Finished.IsZero():
+		return Running
+	case r.Error != "":
+		return Failed
+	}
+	return Succeeded
+}
+
+// Duration returns how long the run took, or has been running
+func (r Run) Duration() time.Duration {
+	if r.Finished.IsZero() {
+		if r.lost {
+			return 0
+		}
+		return time.Since(r.Started)
+	}
+	return r.Finished.Sub(r.Started)
+}
+
+// Output reads the log of the run
+func (r Run) Output() (string, error) {
+	if r.LogPath == "" {
+		return "", nil
+	}
+	content, err := os.ReadFile(r.LogPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read log: %w", err)
+	}
+	return string(content), nil
+}
+
+// Log keeps the runs of this and previous sessions in a directory
+type Log struct {
+	dir    string
+	keep   int
+	mu     sync.Mutex
+	runs   []*Run // oldest first
+	loaded bool
+	seq    int
+	done   chan Run // runs started with Start that finished
+}
+
+// New creates a log storing runs in dir, which is created on the first run
+func New(dir string, keep int) *Log {
+	return &Log{dir: dir, keep: keep, done: make(chan Run, 16)}
+}
+
+// Done delivers the runs started in the background once they finish
+func (l *Log) Done() <-chan Run {
+	return l.done
+}
+
+// Start starts a command in the background and records it; the command's output goes
+// to the log file. The process is waited for, so that it does not remain a zombie
+func (l *Log) Start(run Run, cmd *exec.Cmd) (Run, error) {
+	r, logFile := l.begin(run, cmd, true)
+	if err := cmd.Start(); err != nil {
+		l.finish(r, logFile, err)
+		return l.snapshot(r), err
+	}
+	go func() {
+		l.finish(r, logFile, cmd.Wait())
+		select {
+		case l.done <- l.snapshot(r):
+		default:
+			// Nobody is listening, the run is still in the list
+		}
+	}()
+	return l.snapshot(r), nil
+}
+
+// Run runs a command to completion, recording it, and returns its output
+func (l *Log) Run(run Run, cmd *exec.Cmd) (string, error) {
+	r, logFile := l.begin(run, cmd, true)
+	var buffer bytes.Buffer
+	if logFile == nil {
+		// Without a log file the output is kept in memory
+		cmd.Stdout = &buffer
+		cmd.Stderr = &buffer
+	}
+	err := cmd.Run()
+	l.finish(r, logFile, err)
+	if logFile == nil {
+		return buffer.String(), err
+	}
+	output, readErr := l.snapshot(r).Output()
+	if readErr != nil && err == nil {
+		err = readErr
+	}
+	return output, err
+}
+
+// Stream runs a command to completion, recording it, while copying its output to out
+// The command should have been created with ctx: when ctx is done, the cause of its
+// cancellation, like a timeout, is returned and recorded instead of the kill signal
+func (l *Log) Stream(ctx context.Context, run Run, cmd *exec.Cmd, out io.Writer) error {
+	r, logFile := l.begin(run, cmd, true)
+	output := out
+	if logFile != nil {
+		output = io.MultiWriter(logFile, out)
+	}
+	cmd.Stdout = output
+	cmd.Stderr = output
+	err := cmd.Run()
+	if err != nil && ctx.Err() != nil {
+		err = context.Cause(ctx)
+	}
+	l.finish(r, logFile, err)
+	return err
+}
+
+// Begin records a command whose output goes elsewhere, like the terminal; call the
+// returned function with the result when it exits
+func (l *Log) Begin(run Run, cmd *exec.Cmd) func(error) {
+	r, _ := l.begin(run, cmd, false)
+	return func(err error) {
+		l.finish(r, nil, err)
+	}
+}
+
+// begin records a run; when capturing, the command's output goes to the returned log file
+// Logging never keeps a command from running: without a log file the run records why and
+// the output is left to the caller
+func (l *Log) begin(run Run, cmd *exec.Cmd, capture bool) (*Run, *os.File) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.load()
+
+	dirErr := os.MkdirAll(l.dir, 0755)
+
+	l.seq++
+	run.Started = time.Now()
+	run.ID = fmt.Sprintf("%s-%d-%d", run.Started.Format("20060102-150405"), os.Getpid(), l.seq)
+	if run.Command == "" {
+		run.Command = strings.Join(cmd.Args, " ")
+	}
+
+	var logFile *os.File
+	if capture {
+		var err error
+		if dirErr != nil {
+			run.LogError = fmt.Sprintf("failed to create runs directory: %v", dirErr)
+		} else if logFile, err = os.Create(filepath.Join(l.dir, run.ID+".log")); err != nil {
+			run.LogError = fmt.Sprintf("failed to create log file: %v", err)
+		} else {
+			run.LogPath = logFile.Name()
+			cmd.Stdout = logFile
+			cmd.Stderr = logFile
+		}
+	}
+
+	r := &run
+	l.runs = append(l.runs, r)
+	l.save(r)
+	l.prune()
+	return r, logFile
+}
+
+func (l *Log) finish(r *Run, logFile *os.File, err error) {
+	if logFile != nil {
+		_ = logFile.Close()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	r.Finished = time.Now()
+	if err != nil {
+		r.Error = err.Error()
+		r.ExitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			r.ExitCode = exitErr.ExitCode()
+		}
+	}
+	l.save(r)
+}
+
+func (l *Log) snapshot(r *Run) Run {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return *r
+}
+
+// Runs returns the recorded runs, newest first
+func (l *Log) Runs() []Run {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.load()
+
+	result := make([]Run, len(l.runs))
+	for i, r := range l.runs {
+		result[len(l.runs)-1-i] = *r
+	}
+	return result
+}
+
+// Running counts the runs that have not finished yet
+func (l *Log) Running() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	count := 0
+	for _, r := range l.runs {
+		if r.Status() == Running {
+			count++
+		}
+	}
+	return count
+}
+
+// load reads the runs of previous sessions once
+func (l *Log) load() {
+	if l.loaded {
+		return
+	}
+	l.loaded = true
+
+	paths, _ := filepath.Glob(filepath.Join(l.dir, "*.json"))
+	var previous []*Run
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var r Run
+		if err := json.Unmarshal(content, &r); err != nil {
+			continue
+		}
+		r.lost = r.Finished.IsZero()
+		previous = append(previous, &r)
+	}
+	sort.Slice(previous, func(i, j int) bool {
+		return previous[i].Started.Before(previous[j].Started)
+	})
+	l.runs = append(previous, l.runs...)
+}
+
+// save writes the metadata of a run next to its log
+func (l *Log) save(r *Run) {
+	content, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(filepath.Join(l.dir, r.ID+".json"), content, 0644)
+}
+
+// prune deletes the oldest finished runs beyond the number to keep
+func (l *Log) prune() {
+	for len(l.runs) > l.keep {
+		index := -1
+		for i, r := range l.runs {
+			if r.Status() != Running {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return
+		}
+		r := l.runs[index]
+		_ = os.Remove(filepath.Join(l.dir, r.ID+".json"))
+		if r.LogPath != "" {
+			_ = os.Remove(r.LogPath)
+		}
+		l.runs = append(l.runs[:index], l.runs[index+1:]...)
+	}
+}
@@ -0,0 +1,168 @@
+package runs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func waitDone(t *testing.T, l *Log) Run {
+	t.Helper()
+	select {
+	case run := <-l.Done():
+		return run
+	case <-time.After(5 * time.Second):
+		t.Fatal("run did not finish")
+		return Run{}
+	}
+}
+
+func TestStart(t *testing.T) {
+	l := New(t.TempDir(), DefaultKeep)
+
+	run, err := l.Start(Run{Name: "ok"}, exec.Command("sh", "-c", "echo out; echo err >&2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Status() != Running && run.Status() != Succeeded {
+		t.Errorf("status after start = %s", run.Status())
+	}
+	run = waitDone(t, l)
+	if run.Status() != Succeeded || run.ExitCode != 0 {
+		t.Errorf("status = %s, exit code %d", run.Status(), run.ExitCode)
+	}
+	if output, _ := run.Output(); output != "out\nerr\n" {
+		t.Errorf("output = %q", output)
+	}
+
+	if _, err := l.Start(Run{Name: "fails"}, exec.Command("sh", "-c", "exit 3")); err != nil {
+		t.Fatal(err)
+	}
+	if run := waitDone(t, l); run.Status() != Failed || run.ExitCode != 3 {
+		t.Errorf("status = %s, exit code %d", run.Status(), run.ExitCode)
+	}
+
+	if _, err := l.Start(Run{Name: "missing"}, exec.Command("/nonexistent/command")); err == nil {
+		t.Error("expected an error for a missing command")
+	}
+
+	runs := l.Runs()
+	if len(runs) != 3 || runs[0].Name != "missing" || runs[0].ExitCode != -1 {
+		t.Errorf("runs = %+v", runs)
+	}
+	if l.Running() != 0 {
+		t.Errorf("running = %d", l.Running())
+	}
+}
+
+func TestRunAndBegin(t *testing.T) {
+	l := New(t.TempDir(), DefaultKeep)
+
+	output, err := l.Run(Run{Name: "captured", Command: "greeting"}, exec.Command("echo", "hello"))
+	if err != nil || output != "hello\n" {
+		t.Errorf("output = %q, err = %v", output, err)
+	}
+
+	done := l.Begin(Run{Name: "foreground"}, exec.Command("vim"))
+	if runs := l.Runs(); runs[0].Status() != Running || runs[0].LogPath != "" || runs[0].Command != "vim" {
+		t.Errorf("foreground run = %+v", runs[0])
+	}
+	done(nil)
+	if runs := l.Runs(); runs[0].Status() != Succeeded || runs[1].Command != "greeting" {
+		t.Errorf("runs = %+v", runs)
+	}
+}
+
+func TestStream(t *testing.T) {
+	l := New(t.TempDir(), DefaultKeep)
+
+	var out strings.Builder
+	ctx := context.Background()
+	if err := l.Stream(ctx, Run{Name: "streamed"}, exec.CommandContext(ctx, "sh", "-c", "echo one; echo two >&2"), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "one\ntwo\n" {
+		t.Errorf("streamed output = %q", out.String())
+	}
+	if output, _ := l.Runs()[0].Output(); output != out.String() {
+		t.Errorf("logged output = %q", output)
+	}
+
+	timeout := errors.New("timed out")
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 100*time.Millisecond, timeout)
+	defer cancel()
+	err := l.Stream(ctx, Run{Name: "slow"}, exec.CommandContext(ctx, "sleep", "10"), io.Discard)
+	if !errors.Is(err, timeout) {
+		t.Errorf("err = %v, want the cause of the cancellation", err)
+	}
+	if run := l.Runs()[0]; run.Status() != Failed || run.Error != "timed out" {
+		t.Errorf("run = %+v", run)
+	}
+}
+
+func TestLoadAndPrune(t *testing.T) {
+	dir := t.TempDir()
+	l := New(dir, 3)
+	for i := 0; i < 4; i++ {
+		if _, err := l.Run(Run{Name: "run"}, exec.Command("true")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(l.Runs()) != 3 {
+		t.Errorf("kept %d runs, want 3", len(l.Runs()))
+	}
+	logs, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	if len(logs) != 3 {
+		t.Errorf("%d log files left, want 3", len(logs))
+	}
+
+	// A run that never finished, e.g. because workman was killed
+	l.Begin(Run{Name: "interrupted"}, exec.Command("sleep", "1"))
+
+	reloaded := New(dir, 3).Runs()
+	if len(reloaded) != 3 || reloaded[0].Name != "interrupted" || reloaded[0].Status() != Unknown {
+		t.Errorf("reloaded = %+v", reloaded)
+	}
+	if reloaded[1].Status() != Succeeded || !strings.HasSuffix(reloaded[1].LogPath, ".log") {
+		t.Errorf("reloaded run = %+v", reloaded[1])
+	}
+	if _, err := os.Stat(reloaded[1].LogPath); err != nil {
+		t.Errorf("log missing: %v", err)
+	}
+}
+
+func TestRunWithoutLogFile(t *testing.T) {
+	// The runs directory cannot be created below a file
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	l := New(filepath.Join(file, "runs"), DefaultKeep)
+
+	output, err := l.Run(Run{Name: "captured"}, exec.Command("echo", "hello"))
+	if err != nil || output != "hello\n" {
+		t.Errorf("output = %q, err = %v", output, err)
+	}
+	if run := l.Runs()[0]; run.Status() != Succeeded || run.LogPath != "" || run.LogError == "" {
+		t.Errorf("run = %+v", run)
+	}
+
+	var out strings.Builder
+	ctx := context.Background()
+	if err := l.Stream(ctx, Run{Name: "streamed"}, exec.CommandContext(ctx, "echo", "streamed"), &out); err != nil || out.String() != "streamed\n" {
+		t.Errorf("streamed output = %q, err = %v", out.String(), err)
+	}
+
+	if _, err := l.Start(Run{Name: "background"}, exec.Command("true")); err != nil {
+		t.Fatal(err)
+	}
+	if run := waitDone(t, l); run.Status() != Succeeded {
+		t.Errorf("background run = %+v", run)
+	}
+}
@@ -0,0 +1,148 @@
+package state
+
+import "time"
+
+// RepoData is the cached information about a repository
+type RepoData struct {
+	Worktrees []Worktree
+	HasScript bool
+	Err       error // error of the last load, if any
+	LoadedAt  time.Time
+}
+
+// WorktreeData is the cached information about a worktree
+type WorktreeData struct {
+	Submodules []Submodule
+	Notes      string
+	Details    *WorktreeDetails // nil if details were not loaded
+	LoadedAt   time.Time
+}
+
+// Cache holds repository and worktree data so navigation does not have to wait for git
+// Entries are served even when stale and revalidated in the background
+type Cache struct {
+	TTL       time.Duration
+	repos     map[string]RepoData     // by repository name
+	worktrees map[string]WorktreeData // by worktree path
+	loading   map[string]bool         // repository names and worktree paths with a load in flight
+	reload    map[string]bool         // loads in flight that started before a forced load was requested
+}
+
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{
+		TTL:       ttl,
+		repos:     map[string]RepoData{},
+		worktrees: map[string]WorktreeData{},
+		loading:   map[string]bool{},
+		reload:    map[string]bool{},
+	}
+}
+
+// Repo returns the cached data of a repository
+func (c *Cache) Repo(name string) (RepoData, bool) {
+	data, ok := c.repos[name]
+	return data, ok
+}
+
+// SetRepo stores the data of a repository and ends its load
+// Returns true if a forced load was requested or the entry expired meanwhile: the data is stored as stale and has to be loaded again
+func (c *Cache) SetRepo(name string, data RepoData) bool {
+	reload := c.endLoad(repoKey(name), &data.LoadedAt)
+	c.repos[name] = data
+	return reload
+}
+
+// Worktree returns the cached data of a worktree
+func (c *Cache) Worktree(path string) (WorktreeData, bool) {
+	data, ok := c.worktrees[path]
+	return data, ok
+}
+
+// SetWorktree stores the data of a worktree and ends its load; the result is that of SetRepo
+func (c *Cache) SetWorktree(path string, data WorktreeData) bool {
+	reload := c.endLoad(path, &data.LoadedAt)
+	c.worktrees[path] = data
+	return reload
+}
+
+// StartRepoLoad reports whether a repository needs to be (re)loaded and marks the load as in flight
+// Missing and stale entries need a load unless one is in flight already; force ignores the age
+// A forced load requested while one is in flight is reported by SetRepo when that one ends
+func (c *Cache) StartRepoLoad(name string, force bool) bool {
+	data, ok := c.repos[name]
+	return c.startLoad(repoKey(name), ok, data.LoadedAt, force)
+}
+
+// StartWorktreeLoad is StartRepoLoad for worktrees
+func (c *Cache) StartWorktreeLoad(path string, force bool) bool {
+	data, ok := c.worktrees[path]
+	return c.startLoad(path, ok, data.LoadedAt, force)
+}
+
+// RepoLoading reports whether a load of the repository is in flight
+func (c *Cache) RepoLoading(name string) bool {
+	return c.loading[repoKey(name)]
+}
+
+// ExpireRepo marks a cached repository as stale so it is reloaded when shown next; a load in flight is repeated
+func (c *Cache) ExpireRepo(name string) {
+	if c.loading[repoKey(name)] {
+		c.reload[repoKey(name)] = true
+	}
+	if data, ok := c.repos[name]; ok {
+		data.LoadedAt = time.Time{}
+		c.repos[name] = data
+	}
+}
+
+// ExpireWorktree marks a cached worktree as stale so it is reloaded when shown next; a load in flight is repeated
+func (c *Cache) ExpireWorktree(path string) {
+	if c.loading[path] {
+		c.reload[path] = true
+	}
+	if data, ok := c.worktrees[path]; ok {
+		data.LoadedAt = time.Time{}
+		c.worktrees[path] = data
+	}
+}
+
+// InvalidateRepo removes a repository from the cache
+func (c *Cache) InvalidateRepo(name string) {
+	delete(c.repos, name)
+}
+
+// InvalidateWorktree removes a worktree from the cache
+func (c *Cache) InvalidateWorktree(path string) {
+	delete(c.worktrees, path)
+}
+
+func (c *Cache) startLoad(key string, cached bool, loadedAt time.Time, force bool) bool {
+	if c.loading[key] {
+		// The load in flight may have read the data before the change that forced this one
+		if force {
+			c.reload[key] = true
+		}
+		return false
+	}
+	if cached && !force && time.Since(loadedAt) < c.TTL {
+		return false
+	}
+	c.loading[key] = true
+	return true
+}
+
+// endLoad ends a load and reports whether another one was forced meanwhile, marking its data as stale
+func (c *Cache) endLoad(key string, loadedAt *time.Time) bool {
+	reload := c.reload[key]
+	if reload {
+		*loadedAt = time.Time{}
+	}
+	delete(c.loading, key)
+	delete(c.reload, key)
+	return reload
+}
+
+// repoKey keeps repository names apart from worktree paths in the loading set
+func repoKey(name string) string {
+	return "repo:" + name
+}
@@ -0,0 +1,72 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCacheStartRepoLoad(t *testing.T) {
+	c := NewCache(time.Minute)
+
+	// Missing entries need a load, but only once while it is in flight
+	if !c.StartRepoLoad("app", false) {
+		t.Fatal("Expected missing repository to need a load")
+	}
+	if c.StartRepoLoad("app", false) {
+		t.Error("Expected no second load while one is in flight")
+	}
+	if !c.RepoLoading("app") {
+		t.Error("Expected repository to be loading")
+	}
+
+	if c.SetRepo("app", RepoData{LoadedAt: time.Now()}) {
+		t.Error("Expected no reload without a forced load")
+	}
+	if c.RepoLoading("app") {
+		t.Error("Expected load to end when data is stored")
+	}
+	if c.StartRepoLoad("app", false) {
+		t.Error("Expected fresh entry not to need a load")
+	}
+	if !c.StartRepoLoad("app", true) {
+		t.Error("Expected forced load of fresh entry")
+	}
+
+	// Stale entries are served but revalidated
+	c.SetRepo("app", RepoData{LoadedAt: time.Now().Add(-2 * time.Minute)})
+	if _, ok := c.Repo("app"); !ok {
+		t.Error("Expected stale entry to be served")
+	}
+	if !c.StartRepoLoad("app", false) {
+		t.Error("Expected stale entry to need a load")
+	}
+
+	// Repository names and worktree paths don't collide
+	if !c.StartWorktreeLoad("app", false) {
+		t.Error("Expected worktree load independent of repository load")
+	}
+}
+
+func TestCacheForcedLoadWhileLoading(t *testing.T) {
+	c := NewCache(time.Minute)
+	if !c.StartWorktreeLoad("/wt", false) {
+		t.Fatal("Expected missing worktree to need a load")
+	}
+
+	// The load in flight may have read the data before the change; it is served as stale and loaded again
+	if c.StartWorktreeLoad("/wt", true) {
+		t.Error("Expected no second load while one is in flight")
+	}
+	if !c.SetWorktree("/wt", WorktreeData{LoadedAt: time.Now()}) {
+		t.Error("Expected a reload after the forced load request")
+	}
+	if data, ok := c.Worktree("/wt"); !ok || !data.LoadedAt.IsZero() {
+		t.Errorf("Expected data to be stored as stale, got %+v", data)
+	}
+	if !c.StartWorktreeLoad("/wt", false) {
+		t.Fatal("Expected stale worktree to need a load")
+	}
+	if c.SetWorktree("/wt", WorktreeData{LoadedAt: time.Now()}) {
+		t.Error("Expected the reload request to be cleared")
+	}
+}
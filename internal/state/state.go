@@ -1,11 +1,54 @@
 package state
 
-import "github.com/michael-rose/workman/internal/config"
+import (
+	"time"
+
+	"github.com/michael-rose/workman/internal/config"
+)
 
 type Worktree struct {
 	Name   string
 	Branch string
 	Path   string
+	Locked bool // true if the worktree is locked with "git worktree lock"
+}
+
+// Submodule describes a submodule checked out inside a worktree
+type Submodule struct {
+	Path   string
+	SHA    string
+	Status SubmoduleStatus
+	Dirty  bool // true if the submodule has uncommitted changes
+}
+
+type SubmoduleStatus string
+
+const (
+	SubmoduleClean         SubmoduleStatus = "clean"
+	SubmoduleUninitialized SubmoduleStatus = "uninitialized"
+	SubmoduleModified      SubmoduleStatus = "modified" // checked out commit differs from the recorded one
+	SubmoduleConflict      SubmoduleStatus = "conflict"
+)
+
+// Commit describes a single commit in the history of a worktree
+type Commit struct {
+	SHA     string
+	Subject string
+	Date    time.Time
+}
+
+// WorktreeDetails holds the extended information shown for the selected worktree
+type WorktreeDetails struct {
+	Head          Commit
+	RecentCommits []Commit
+	Upstream      string // empty if the branch has no upstream
+	Ahead         int    // commits ahead of upstream
+	Behind        int    // commits behind upstream
+	BaseBranch    string
+	AheadBase     int // commits ahead of the base branch
+	BehindBase    int // commits behind the base branch
+	Changes       int // number of uncommitted changes
+	LastModified  time.Time
 }
 
 type AppState struct {
@@ -14,8 +57,17 @@ type AppState struct {
 	SelectedWTIndex   int
 	ActivePane        Pane // "repos" or "worktrees"
 	Worktrees         []Worktree
+	Submodules        []Submodule      // submodules of the selected worktree
+	Notes             string           // notes of the selected worktree
+	Details           *WorktreeDetails // details of the selected worktree, nil if not loaded
+	ShowDetails       bool
+	Marked            map[string]bool // paths of worktrees marked for bulk operations
+	Cache             *Cache
 }
 
+// cacheTTL is the age after which cached repository and worktree data is revalidated
+const cacheTTL = 30 * time.Second
+
 type Pane string
 
 const (
@@ -30,6 +82,9 @@ func New(cfg *config.Config) *AppState {
 		SelectedWTIndex:   0,
 		ActivePane:        ReposPane,
 		Worktrees:         []Worktree{},
+		ShowDetails:       true,
+		Marked:            map[string]bool{},
+		Cache:             NewCache(cacheTTL),
 	}
 }
 
@@ -82,3 +137,54 @@ func (s *AppState) TogglePane() {
 		s.ActivePane = ReposPane
 	}
 }
+
+// ToggleMark marks or unmarks the selected worktree
+func (s *AppState) ToggleMark() {
+	if s.SelectedWTIndex >= len(s.Worktrees) {
+		return
+	}
+	path := s.Worktrees[s.SelectedWTIndex].Path
+	if s.Marked[path] {
+		delete(s.Marked, path)
+	} else {
+		s.Marked[path] = true
+	}
+}
+
+// ToggleMarkAll marks all worktrees, or clears the marks if all are marked already
+func (s *AppState) ToggleMarkAll() {
+	if len(s.MarkedWorktrees()) == len(s.Worktrees) {
+		s.ClearMarks()
+		return
+	}
+	for _, wt := range s.Worktrees {
+		s.Marked[wt.Path] = true
+	}
+}
+
+// ClearMarks unmarks all worktrees
+func (s *AppState) ClearMarks() {
+	s.Marked = map[string]bool{}
+}
+
+// MarkedWorktrees returns the marked worktrees in list order
+func (s *AppState) MarkedWorktrees() []Worktree {
+	var marked []Worktree
+	for _, wt := range s.Worktrees {
+		if s.Marked[wt.Path] {
+			marked = append(marked, wt)
+		}
+	}
+	return marked
+}
+
+// TargetWorktrees returns the marked worktrees, or the selected one if none are marked
+func (s *AppState) TargetWorktrees() []Worktree {
+	if marked := s.MarkedWorktrees(); len(marked) > 0 {
+		return marked
+	}
+	if s.SelectedWTIndex < len(s.Worktrees) {
+		return []Worktree{s.Worktrees[s.SelectedWTIndex]}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/michael-rose/workman/internal/config"
+)
+
+func TestTargetWorktrees(t *testing.T) {
+	s := New(&config.Config{})
+	s.Worktrees = []Worktree{
+		{Name: "main", Path: "/a"},
+		{Name: "one", Path: "/b"},
+		{Name: "two", Path: "/c"},
+	}
+	s.SelectedWTIndex = 1
+
+	// Without marks the selected worktree is the target
+	if targets := s.TargetWorktrees(); len(targets) != 1 || targets[0].Name != "one" {
+		t.Errorf("Expected selected worktree as target, got %+v", targets)
+	}
+
+	s.SelectedWTIndex = 2
+	s.ToggleMark()
+	s.SelectedWTIndex = 0
+	s.ToggleMark()
+	targets := s.TargetWorktrees()
+	if len(targets) != 2 || targets[0].Name != "main" || targets[1].Name != "two" {
+		t.Errorf("Expected marked worktrees in list order, got %+v", targets)
+	}
+
+	s.ToggleMarkAll()
+	if len(s.MarkedWorktrees()) != 3 {
+		t.Errorf("Expected all worktrees to be marked, got %d", len(s.MarkedWorktrees()))
+	}
+	s.ToggleMarkAll()
+	if len(s.MarkedWorktrees()) != 0 {
+		t.Errorf("Expected marks to be cleared, got %d", len(s.MarkedWorktrees()))
+	}
+}
@@ -0,0 +1,293 @@
+// Package template expands the ${...} variables of yank templates, editor commands,
+// worktree path templates and legacy script substitution
+//
+// A variable may be followed by filters: ${branch_name | slug}, ${upstream | default:none | upper}
+package template
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Variable documents a template variable
+type Variable struct {
+	Name        string
+	Aliases     []string
+	Description string
+}
+
+// Variables lists the variables known to templates
+var Variables = []Variable{
+	{Name: "repo_name", Aliases: []string{"repo"}, Description: "Repository name"},
+	{Name: "repo_path", Description: "Path of the repository"},
+	{Name: "repo_url", Description: "URL of a remote repository"},
+	{Name: "root_dir", Description: "Root directory of worktrees and clones"},
+	{Name: "worktree_path", Aliases: []string{"path"}, Description: "Full path to the worktree"},
+	{Name: "worktree_name", Description: "Worktree name"},
+	{Name: "branch_name", Aliases: []string{"branch"}, Description: "Branch checked out in the worktree"},
+	{Name: "notes", Description: "Notes of the worktree"},
+	{Name: "head", Description: "Commit SHA of HEAD"},
+	{Name: "upstream", Description: "Upstream branch, e.g. origin/main"},
+}
+
+// Filter documents a filter
+type Filter struct {
+	Name        string
+	Description string
+}
+
+// Filters lists the filters a variable can be piped through
+var Filters = []Filter{
+	{Name: "slug", Description: "Lowercase letters, digits and dashes: feature/Login → feature-login"},
+	{Name: "quote", Description: "Quote as a single shell word"},
+	{Name: "upper", Description: "Uppercase"},
+	{Name: "lower", Description: "Lowercase"},
+	{Name: "trim", Description: "Remove surrounding whitespace"},
+	{Name: "short", Description: "First 7 characters, for commit SHAs"},
+	{Name: "default:TEXT", Description: "TEXT if the value is empty"},
+}
+
+// Vars holds the values of variables by name
+type Vars map[string]string
+
+// Template is a parsed template
+type Template struct {
+	parts []part
+}
+
+// part is either literal text or a variable with its filters
+type part struct {
+	text     string
+	variable string
+	filters  []filter
+}
+
+type filter struct {
+	name string
+	arg  string
+}
+
+var canonical = func() map[string]string {
+	names := map[string]string{}
+	for _, v := range Variables {
+		names[v.Name] = v.Name
+		for _, alias := range v.Aliases {
+			names[alias] = v.Name
+		}
+	}
+	return names
+}()
+
+// Parse parses a template, reporting unknown variables and filters
+func Parse(text string) (*Template, error) {
+	return parse(text, false)
+}
+
+// ParseScript parses a script for legacy substitution: ${...} that are not template variables,
+// like ${HOME}, are left to the shell and values are shell-quoted unless the last filter is quote
+func ParseScript(text string) *Template {
+	t, _ := parse(text, true)
+	return t
+}
+
+func parse(text string, script bool) (*Template, error) {
+	t := &Template{}
+	var literal strings.Builder
+	for {
+		start := strings.Index(text, "${")
+		if start < 0 {
+			literal.WriteString(text)
+			break
+		}
+		// $${ is a literal ${
+		if start > 0 && text[start-1] == '$' {
+			literal.WriteString(text[:start-1] + "${")
+			text = text[start+2:]
+			continue
+		}
+		end := strings.Index(text[start:], "}")
+		if end < 0 {
+			if script {
+				literal.WriteString(text)
+				break
+			}
+			return nil, fmt.Errorf("unterminated ${ at %q", text[start:])
+		}
+		expr := text[start+2 : start+end]
+		p, err := parseExpr(expr)
+		if err != nil {
+			if !script {
+				return nil, err
+			}
+			literal.WriteString(text[:start+end+1])
+			text = text[start+end+1:]
+			continue
+		}
+		if script && (len(p.filters) == 0 || p.filters[len(p.filters)-1].name != "quote") {
+			p.filters = append(p.filters, filter{name: "quote"})
+		}
+
+		literal.WriteString(text[:start])
+		if literal.Len() > 0 {
+			t.parts = append(t.parts, part{text: literal.String()})
+			literal.Reset()
+		}
+		t.parts = append(t.parts, p)
+		text = text[start+end+1:]
+	}
+	if literal.Len() > 0 {
+		t.parts = append(t.parts, part{text: literal.String()})
+	}
+	return t, nil
+}
+
+func parseExpr(expr string) (part, error) {
+	fields := strings.Split(expr, "|")
+	name := strings.TrimSpace(fields[0])
+	variable, ok := canonical[name]
+	if !ok {
+		return part{}, fmt.Errorf("unknown variable '%s' (available: %s)", name, names())
+	}
+
+	p := part{variable: variable}
+	for _, field := range fields[1:] {
+		name, arg, hasArg := strings.Cut(strings.TrimSpace(field), ":")
+		name = strings.TrimSpace(name)
+		switch name {
+		case "slug", "quote", "upper", "lower", "trim", "short":
+			if hasArg {
+				return part{}, fmt.Errorf("filter '%s' takes no argument", name)
+			}
+		case "default":
+			arg = strings.TrimSpace(arg)
+			if len(arg) >= 2 && arg[0] == '"' && arg[len(arg)-1] == '"' {
+				arg = arg[1 : len(arg)-1]
+			}
+		default:
+			return part{}, fmt.Errorf("unknown filter '%s' (available: slug, quote, upper, lower, trim, short, default)", name)
+		}
+		p.filters = append(p.filters, filter{name: name, arg: arg})
+	}
+	return p, nil
+}
+
+func names() string {
+	var result []string
+	for _, v := range Variables {
+		result = append(result, v.Name)
+	}
+	return strings.Join(result, ", ")
+}
+
+// Uses reports whether the template refers to a variable
+func (t *Template) Uses(variable string) bool {
+	for _, p := range t.parts {
+		if p.variable == variable {
+			return true
+		}
+	}
+	return false
+}
+
+// Variables returns the variables the template refers to
+func (t *Template) Variables() []string {
+	var result []string
+	seen := map[string]bool{}
+	for _, p := range t.parts {
+		if p.variable != "" && !seen[p.variable] {
+			seen[p.variable] = true
+			result = append(result, p.variable)
+		}
+	}
+	return result
+}
+
+// Execute expands the template
+func (t *Template) Execute(vars Vars) string {
+	var b strings.Builder
+	for _, p := range t.parts {
+		if p.variable == "" {
+			b.WriteString(p.text)
+		} else {
+			b.WriteString(p.value(vars))
+		}
+	}
+	return b.String()
+}
+
+// ExecuteFields expands the template into command arguments: literal text is split at
+// whitespace while values are never split, so paths with spaces stay one argument
+func (t *Template) ExecuteFields(vars Vars) []string {
+	var fields []string
+	var current strings.Builder
+	inField := false
+	for _, p := range t.parts {
+		if p.variable != "" {
+			current.WriteString(p.value(vars))
+			inField = true
+			continue
+		}
+		for _, r := range p.text {
+			if unicode.IsSpace(r) {
+				if inField {
+					fields = append(fields, current.String())
+					current.Reset()
+					inField = false
+				}
+				continue
+			}
+			current.WriteRune(r)
+			inField = true
+		}
+	}
+	if inField {
+		fields = append(fields, current.String())
+	}
+	return fields
+}
+
+func (p part) value(vars Vars) string {
+	value := vars[p.variable]
+	for _, f := range p.filters {
+		value = f.apply(value)
+	}
+	return value
+}
+
+var slugCleaner = regexp.MustCompile(`[^a-z0-9]+`)
+
+func (f filter) apply(value string) string {
+	switch f.name {
+	case "slug":
+		return strings.Trim(slugCleaner.ReplaceAllString(strings.ToLower(value), "-"), "-")
+	case "quote":
+		return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+	case "upper":
+		return strings.ToUpper(value)
+	case "lower":
+		return strings.ToLower(value)
+	case "trim":
+		return strings.TrimSpace(value)
+	case "short":
+		if len(value) > 7 {
+			return value[:7]
+		}
+		return value
+	case "default":
+		if value == "" {
+			return f.arg
+		}
+	}
+	return value
+}
+
+// Render parses and expands a template
+func Render(text string, vars Vars) (string, error) {
+	t, err := Parse(text)
+	if err != nil {
+		return "", err
+	}
+	return t.Execute(vars), nil
+}
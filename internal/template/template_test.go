@@ -0,0 +1,94 @@
+package template
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var vars = Vars{
+	"repo_name":     "My Repo",
+	"worktree_path": "/work/my repo/feature",
+	"worktree_name": "feature",
+	"branch_name":   "Feature/Login_Page",
+	"head":          "0123456789abcdef",
+	"notes":         "",
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{"${worktree_path}", "/work/my repo/feature"},
+		{"cd ${path} && ${branch}", "cd /work/my repo/feature && Feature/Login_Page"},
+		{"${repo_name | slug}-${branch_name | slug}", "my-repo-feature-login-page"},
+		{"${branch_name|upper}", "FEATURE/LOGIN_PAGE"},
+		{"${repo | lower}", "my repo"},
+		{"${head | short}", "0123456"},
+		{"cd ${worktree_path | quote}", "cd '/work/my repo/feature'"},
+		{"${notes | default:no notes}", "no notes"},
+		{`${notes | default:"-" | upper}`, "-"},
+		{"${upstream | default:none}", "none"},
+		{"$${HOME} and $PATH", "${HOME} and $PATH"},
+	}
+
+	for _, tt := range tests {
+		got, err := Render(tt.template, vars)
+		if err != nil {
+			t.Errorf("Render(%q): %v", tt.template, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("Render(%q) = %q, want %q", tt.template, got, tt.expected)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := map[string]string{
+		"${unknown}":              "unknown variable 'unknown'",
+		"${branch | reverse}":     "unknown filter 'reverse'",
+		"${branch | slug:x}":      "takes no argument",
+		"cd ${worktree_path":      "unterminated",
+		"${worktree_path}${HOME}": "unknown variable 'HOME'",
+	}
+	for text, expected := range tests {
+		_, err := Parse(text)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("Parse(%q) error = %v, want %q", text, err, expected)
+		}
+	}
+}
+
+func TestExecuteFields(t *testing.T) {
+	tmpl, err := Parse("idea  --title ${repo_name}:${branch_name | slug}\t${path}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"idea", "--title", "My Repo:feature-login-page", "/work/my repo/feature"}
+	if got := tmpl.ExecuteFields(vars); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %q, want %q", got, expected)
+	}
+	if !tmpl.Uses("worktree_path") || tmpl.Uses("head") {
+		t.Errorf("Uses reports the wrong variables: %v", tmpl.Variables())
+	}
+}
+
+func TestParseScript(t *testing.T) {
+	script := `cd ${worktree_path}
+echo "${HOME:-/root}" ${branch | quote} ${notes | default:none}
+git log ${branch_name}`
+	expected := `cd '/work/my repo/feature'
+echo "${HOME:-/root}" 'Feature/Login_Page' 'none'
+git log 'Feature/Login_Page'`
+	if got := ParseScript(script).Execute(vars); got != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", got, expected)
+	}
+
+	// Values never break out of their quotes
+	evil := Vars{"branch_name": `x'; touch pwned; '`}
+	if got := ParseScript("echo ${branch_name}").Execute(evil); got != `echo 'x'\''; touch pwned; '\'''` {
+		t.Errorf("got %s", got)
+	}
+}
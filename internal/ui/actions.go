@@ -0,0 +1,286 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/michael-rose/workman/internal/config"
+	"github.com/michael-rose/workman/internal/git"
+	"github.com/michael-rose/workman/internal/state"
+)
+
+// action is a command of the main view, run by its key binding or from the command palette
+// Several actions can share a binding when they apply to different contexts, like adding
+// a repository or a worktree
+type action struct {
+	name        string           // name of the binding in the KeyMap and the [keys] table
+	title       string           // shown in the command palette; navigation actions have none
+	markedTitle string           // title while worktrees are marked, if it differs
+	enabled     func(Model) bool // whether the action applies to the current context; nil for always
+	run         func(Model) (tea.Model, tea.Cmd)
+}
+
+// paletteTitle returns the title of the action in the current context
+func (a action) paletteTitle(m Model) string {
+	if a.markedTitle != "" && len(m.state.MarkedWorktrees()) > 0 {
+		return a.markedTitle
+	}
+	return a.title
+}
+
+// available reports whether the action applies to the current context
+func (a action) available(m Model) bool {
+	return a.enabled == nil || a.enabled(m)
+}
+
+// Contexts of actions
+func inRepos(m Model) bool { return m.state.ActivePane == state.ReposPane }
+
+func repoSelected(m Model) bool { return inRepos(m) && m.state.GetSelectedRepo() != nil }
+
+func inWorktrees(m Model) bool {
+	return m.state.ActivePane == state.WorktreesPane && m.state.GetSelectedRepo() != nil
+}
+
+func worktreeSelected(m Model) bool { return inWorktrees(m) && len(m.state.Worktrees) > 0 }
+
+func hasWorktrees(m Model) bool {
+	return m.state.GetSelectedRepo() != nil && len(m.state.Worktrees) > 0
+}
+
+func hasMarks(m Model) bool { return len(m.state.MarkedWorktrees()) > 0 }
+
+// actionRegistry lists all actions of the main view in the order of the command palette
+func actionRegistry() []action {
+	return []action{
+		// Navigation
+		{name: "up", run: Model.selectPrev},
+		{name: "down", run: Model.selectNext},
+		{name: "page_up", run: func(m Model) (tea.Model, tea.Cmd) { return m.moveSelection(-m.activeScroll().pageSize()) }},
+		{name: "page_down", run: func(m Model) (tea.Model, tea.Cmd) { return m.moveSelection(m.activeScroll().pageSize()) }},
+		{name: "top", run: func(m Model) (tea.Model, tea.Cmd) { return m.selectIndex(m.state.ActivePane, 0) }},
+		{name: "bottom", run: func(m Model) (tea.Model, tea.Cmd) { return m.selectIndex(m.state.ActivePane, math.MaxInt) }},
+		{name: "switch_pane", title: "Switch pane", run: Model.switchPane},
+		{name: "repos_pane", title: "Focus repositories", enabled: not(inRepos), run: Model.focusRepos},
+		{name: "worktrees_pane", title: "Focus worktrees", enabled: inRepos, run: func(m Model) (tea.Model, tea.Cmd) {
+			m.state.ActivePane = state.WorktreesPane
+			return m, nil
+		}},
+
+		// Repositories
+		{name: "add", title: "Add repository", enabled: inRepos, run: Model.openAddRepo},
+		{name: "delete", title: "Delete repository", enabled: repoSelected, run: Model.confirmDeleteRepo},
+		{name: "script", title: "Edit post-create script", enabled: repoSelected, run: Model.editScript},
+		{name: "refresh", title: "Refresh", run: Model.refreshSelected},
+		{name: "refresh_all", title: "Refresh all repositories", run: func(m Model) (tea.Model, tea.Cmd) {
+			return m, m.refreshAllCmd(true)
+		}},
+
+		// Worktrees
+		{name: "add", title: "Add worktree", enabled: inWorktrees, run: Model.openAddWorktree},
+		{name: "delete", title: "Delete marked worktrees", enabled: and(inWorktrees, hasMarks), run: Model.confirmDeleteMarked},
+		{name: "delete", title: "Delete worktree", enabled: func(m Model) bool {
+			// The main worktree (first one) cannot be deleted
+			return worktreeSelected(m) && !hasMarks(m) && m.state.SelectedWTIndex > 0
+		}, run: Model.confirmDeleteWorktree},
+		{name: "open", title: "Run action", markedTitle: "Run action on marked worktrees", enabled: worktreeSelected, run: Model.launch},
+		{name: "editor", title: "Open in editor", enabled: worktreeSelected, run: Model.openInEditor},
+		{name: "notes", title: "Edit notes", enabled: worktreeSelected, run: Model.editNotes},
+		{name: "details", title: "Toggle details", run: func(m Model) (tea.Model, tea.Cmd) {
+			m.state.ShowDetails = !m.state.ShowDetails
+			return m.showWorktree()
+		}},
+		{name: "diff", title: "Show diff", enabled: worktreeSelected, run: Model.openDiffView},
+		{name: "stash", title: "Manage stashes", enabled: worktreeSelected, run: Model.openStashView},
+		{name: "sync", title: "Sync with base branch", enabled: worktreeSelected, run: Model.startSync},
+		{name: "sync_all", title: "Sync all worktrees with base branch", enabled: hasWorktrees, run: Model.startBulkSync},
+		{name: "push", title: "Push branch", markedTitle: "Push marked worktrees", enabled: worktreeSelected, run: Model.startPush},
+		{name: "push_all", title: "Push all worktrees", enabled: hasWorktrees, run: Model.confirmPushAll},
+		{name: "fetch", title: "Fetch", markedTitle: "Fetch marked worktrees", enabled: worktreeSelected, run: Model.fetchTargets},
+		{name: "fetch_all", title: "Fetch all worktrees", enabled: hasWorktrees, run: Model.fetchAll},
+		{name: "lock", title: "Lock/unlock worktree", markedTitle: "Lock/unlock marked worktrees", enabled: worktreeSelected, run: Model.toggleLock},
+		{name: "yank", title: "Yank marked worktrees", enabled: and(worktreeSelected, hasMarks), run: Model.yankMarked},
+		{name: "yank", title: "Yank worktree", enabled: and(worktreeSelected, not(hasMarks)), run: Model.yankWorktreeCommand},
+		{name: "cleanup", title: "Clean up branches", enabled: hasWorktrees, run: Model.openCleanupView},
+
+		// Marks
+		{name: "mark", title: "Mark/unmark worktree", enabled: worktreeSelected, run: func(m Model) (tea.Model, tea.Cmd) {
+			m.state.ToggleMark()
+			m.state.NextWorktree()
+			return m.showWorktree()
+		}},
+		{name: "mark_all", title: "Mark all worktrees", enabled: worktreeSelected, run: func(m Model) (tea.Model, tea.Cmd) {
+			m.state.ToggleMarkAll()
+			return m, nil
+		}},
+		{name: "clear_marks", title: "Clear marks", enabled: hasMarks, run: func(m Model) (tea.Model, tea.Cmd) {
+			m.state.ClearMarks()
+			return m, nil
+		}},
+
+		// Layout
+		{name: "shrink_repos", title: "Shrink repositories pane", run: func(m Model) (tea.Model, tea.Cmd) {
+			return m.resizeRepos(m.state.Config.Split - splitStep)
+		}},
+		{name: "grow_repos", title: "Grow repositories pane", run: func(m Model) (tea.Model, tea.Cmd) {
+			return m.resizeRepos(m.state.Config.Split + splitStep)
+		}},
+		{name: "toggle_repos", title: "Hide/show repositories pane", run: func(m Model) (tea.Model, tea.Cmd) {
+			return m.toggleRepos()
+		}},
+
+		// General
+		{name: "all_worktrees", title: "Show all worktrees", enabled: func(m Model) bool {
+			return len(m.state.Config.Repositories) > 0
+		}, run: Model.openOverview},
+		{name: "runs", title: "Show script runs", run: Model.openRuns},
+		{name: "palette", run: Model.openPalette},
+		{name: "help", title: "Show key bindings", run: Model.openHelp},
+		{name: "quit", title: "Quit", run: func(m Model) (tea.Model, tea.Cmd) { return m, tea.Quit }},
+	}
+}
+
+func not(f func(Model) bool) func(Model) bool {
+	return func(m Model) bool { return !f(m) }
+}
+
+func and(a, b func(Model) bool) func(Model) bool {
+	return func(m Model) bool { return a(m) && b(m) }
+}
+
+// runKey runs the first action available in the current context whose binding matches a key
+func (m Model) runKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	for _, a := range actionRegistry() {
+		b, ok := m.keys.lookup(a.name)
+		if ok && key.Matches(msg, b) && a.available(m) {
+			return a.run(m)
+		}
+	}
+	if model, cmd, ok := m.runActionKey(msg); ok {
+		return model, cmd
+	}
+	return m, nil
+}
+
+// selectPrev selects the previous item of the active pane, wrapping around at the top
+func (m Model) selectPrev() (tea.Model, tea.Cmd) {
+	if m.state.ActivePane == state.ReposPane {
+		m.state.PrevRepo()
+		return m.showRepo()
+	}
+	m.state.PrevWorktree()
+	return m.showWorktree()
+}
+
+// selectNext selects the next item of the active pane, wrapping around at the bottom
+func (m Model) selectNext() (tea.Model, tea.Cmd) {
+	if m.state.ActivePane == state.ReposPane {
+		m.state.NextRepo()
+		return m.showRepo()
+	}
+	m.state.NextWorktree()
+	return m.showWorktree()
+}
+
+// switchPane toggles the active pane; switching to the hidden repositories pane shows it again
+func (m Model) switchPane() (tea.Model, tea.Cmd) {
+	if m.state.Config.HideRepos {
+		m.state.ActivePane = state.ReposPane
+		return m.toggleRepos()
+	}
+	m.state.TogglePane()
+	return m, nil
+}
+
+// focusRepos activates the repositories pane, showing it if it is hidden
+func (m Model) focusRepos() (tea.Model, tea.Cmd) {
+	m.state.ActivePane = state.ReposPane
+	if m.state.Config.HideRepos {
+		return m.toggleRepos()
+	}
+	return m, nil
+}
+
+// refreshSelected refreshes the selected repository and worktree
+func (m Model) refreshSelected() (tea.Model, tea.Cmd) {
+	repo := m.state.GetSelectedRepo()
+	if repo == nil {
+		return m, nil
+	}
+	cmds := []tea.Cmd{m.refreshRepoCmd(*repo, true)}
+	if m.state.SelectedWTIndex < len(m.state.Worktrees) {
+		cmds = append(cmds, m.refreshWorktreeCmd(*repo, m.state.Worktrees[m.state.SelectedWTIndex], true))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m Model) openAddRepo() (tea.Model, tea.Cmd) {
+	m.dialogType = DialogAddRepo
+	m.addRepoDialog = NewAddRepoDialog()
+	m.errorMsg = ""
+	m.successMsg = ""
+	return m, nil
+}
+
+func (m Model) openAddWorktree() (tea.Model, tea.Cmd) {
+	repo := m.state.GetSelectedRepo()
+
+	// Fetch branches for autocomplete
+	branches, err := git.ListBranches(repo.Path)
+	if err != nil {
+		branches = []string{} // If fetch fails, continue with empty list
+	}
+
+	m.dialogType = DialogAddWorktree
+	m.addWorktreeDialog = NewAddWorktreeDialog(branches)
+	m.errorMsg = ""
+	m.successMsg = ""
+	return m, nil
+}
+
+func (m Model) confirmDeleteRepo() (tea.Model, tea.Cmd) {
+	m.dialogType = DialogConfirmDeleteRepo
+	m.confirmDeleteRepoDialog = NewConfirmDeleteRepositoryDialog(m.state.GetSelectedRepo().Name)
+	m.errorMsg = ""
+	m.successMsg = ""
+	return m, nil
+}
+
+func (m Model) confirmDeleteMarked() (tea.Model, tea.Cmd) {
+	marked := m.state.MarkedWorktrees()
+	m.dialogType = DialogConfirmDelete
+	m.confirmDeleteDialog = NewConfirmDeleteMarkedDialog(marked)
+	m.errorMsg = ""
+	m.successMsg = ""
+	return m, deleteWarningsCmd(marked)
+}
+
+func (m Model) confirmDeleteWorktree() (tea.Model, tea.Cmd) {
+	selectedWT := m.state.Worktrees[m.state.SelectedWTIndex]
+	m.dialogType = DialogConfirmDelete
+	m.confirmDeleteDialog = NewConfirmDeleteDialog(selectedWT)
+	m.errorMsg = ""
+	m.successMsg = ""
+	return m, deleteWarningsCmd([]state.Worktree{selectedWT})
+}
+
+func (m Model) editNotes() (tea.Model, tea.Cmd) {
+	selectedWT := m.state.Worktrees[m.state.SelectedWTIndex]
+	repo := m.state.GetSelectedRepo()
+	currentNotes, err := config.GetWorktreeNotes(repo.Name, selectedWT.Name)
+	if err != nil {
+		return m, showError(fmt.Sprintf("Failed to load notes: %v", err))
+	}
+	return m.openEditor(editNotesTarget, repo.Name, selectedWT.Name, currentNotes)
+}
+
+func (m Model) editScript() (tea.Model, tea.Cmd) {
+	repo := m.state.GetSelectedRepo()
+	currentScript, err := config.GetRepoScript(repo.Name)
+	if err != nil {
+		return m, showError(fmt.Sprintf("Failed to load script: %v", err))
+	}
+	return m.openEditor(editScriptTarget, repo.Name, "", currentScript)
+}
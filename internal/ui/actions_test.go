@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/michael-rose/workman/internal/config"
+	"github.com/michael-rose/workman/internal/state"
+)
+
+func TestActionRegistryMatchesKeyMap(t *testing.T) {
+	keys := DefaultKeyMap()
+	registered := map[string]bool{}
+	for _, a := range actionRegistry() {
+		if _, ok := keys.lookup(a.name); !ok {
+			t.Errorf("action '%s' has no binding in the key map", a.name)
+		}
+		registered[a.name] = true
+	}
+	for _, k := range keys.actions() {
+		if !registered[k.name] {
+			t.Errorf("binding '%s' runs no action", k.name)
+		}
+	}
+}
+
+func paletteTitles(m Model) map[string]string {
+	titles := map[string]string{}
+	for _, entry := range m.paletteEntries() {
+		titles[entry.title] = entry.keys
+	}
+	return titles
+}
+
+func TestPaletteEntriesFollowContext(t *testing.T) {
+	cfg := &config.Config{Repositories: []config.Repository{{Name: "repo", Path: "/tmp/repo"}}}
+	s := state.New(cfg)
+	m := NewModel(s, DefaultKeyMap())
+
+	titles := paletteTitles(m)
+	if titles["Add repository"] != "+" {
+		t.Errorf("add repository: keys %q", titles["Add repository"])
+	}
+	if _, ok := titles["Add worktree"]; ok {
+		t.Error("worktree action offered in the repositories pane")
+	}
+
+	s.ActivePane = state.WorktreesPane
+	s.Worktrees = []state.Worktree{{Name: "main", Path: "/tmp/repo"}, {Name: "feature", Path: "/tmp/feature"}}
+	titles = paletteTitles(m)
+	if _, ok := titles["Add worktree"]; !ok {
+		t.Error("add worktree not offered in the worktrees pane")
+	}
+	if keys, ok := titles["Fetch all worktrees"]; !ok || keys != "" {
+		t.Errorf("fetch all: offered %v, keys %q", ok, keys)
+	}
+	if _, ok := titles["Delete worktree"]; ok {
+		t.Error("deleting the main worktree offered")
+	}
+
+	s.Marked = map[string]bool{"/tmp/feature": true}
+	titles = paletteTitles(m)
+	if _, ok := titles["Push marked worktrees"]; !ok {
+		t.Error("push not titled for the marked worktrees")
+	}
+}
+
+func TestPaletteFilter(t *testing.T) {
+	v := NewPaletteView([]paletteEntry{
+		{title: "Add repository"},
+		{title: "Push all worktrees"},
+		{title: "Fetch all worktrees"},
+		{title: "Show all worktrees"},
+	}, 80, 30)
+
+	v.input.SetValue("all w")
+	v.filter()
+	if len(v.matches) != 3 || v.matches[0].title != "Push all worktrees" {
+		t.Errorf("substring matches: %v", v.matches)
+	}
+
+	// Fuzzy matches come after titles containing the input
+	v.input.SetValue("fa")
+	v.filter()
+	if len(v.matches) != 1 || v.matches[0].title != "Fetch all worktrees" {
+		t.Errorf("fuzzy matches: %v", v.matches)
+	}
+
+	v.input.SetValue("rep")
+	v.filter()
+	if entry, ok := v.Selected(); !ok || entry.title != "Add repository" {
+		t.Errorf("selected %v, %v", entry, ok)
+	}
+}
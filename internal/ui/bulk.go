@@ -0,0 +1,220 @@
+package ui
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/michael-rose/workman/internal/config"
+	"github.com/michael-rose/workman/internal/git"
+	"github.com/michael-rose/workman/internal/runs"
+	"github.com/michael-rose/workman/internal/state"
+)
+
+// reportMsg is sent when an operation on one or more worktrees has finished
+type reportMsg struct {
+	title  string
+	output string
+	failed bool
+}
+
+// bulkReport collects the per-worktree results of an operation
+type bulkReport struct {
+	b      strings.Builder
+	failed bool
+}
+
+func (r *bulkReport) success(name, detail string) {
+	if detail != "" {
+		name += " " + detail
+	}
+	r.b.WriteString(fmt.Sprintf("✓ %s\n", name))
+}
+
+func (r *bulkReport) failure(name string, err error) {
+	r.failed = true
+	r.b.WriteString(fmt.Sprintf("✗ %s: %v\n", name, err))
+}
+
+// output appends the output of a command below the result line
+func (r *bulkReport) output(text string) {
+	if text = strings.TrimSpace(text); text != "" {
+		r.b.WriteString(text)
+		r.b.WriteString("\n\n")
+	}
+}
+
+func (r *bulkReport) msg(title string) reportMsg {
+	return reportMsg{title: title, output: r.b.String(), failed: r.failed}
+}
+
+// bulkCmd runs fn for each worktree in the background and reports the results
+// fn returns an optional detail for the result line and the command output
+func bulkCmd(title string, worktrees []state.Worktree, fn func(wt state.Worktree) (string, string, error)) tea.Cmd {
+	return func() tea.Msg {
+		var report bulkReport
+		for _, wt := range worktrees {
+			detail, output, err := fn(wt)
+			if err != nil {
+				report.failure(wt.Name, err)
+			} else {
+				report.success(wt.Name, detail)
+			}
+			report.output(output)
+		}
+		return report.msg(title)
+	}
+}
+
+// handleReport refreshes the worktree list and shows the result report
+func (m Model) handleReport(msg reportMsg) (tea.Model, tea.Cmd) {
+	m, cmd := m.reloadWorktrees()
+	m.successMsg = ""
+	m.errorMsg = ""
+	m.outputView = NewOutputView(msg.title, msg.output, msg.failed, m.width, m.height)
+	m.dialogType = DialogOutput
+	return m, cmd
+}
+
+// deleteMarkedWorktrees removes the marked worktrees with their branches and notes
+func (m Model) deleteMarkedWorktrees() (tea.Model, tea.Cmd) {
+	repo := m.state.GetSelectedRepo()
+	repoPath, repoName := repo.Path, repo.Name
+	mainPath := m.state.Worktrees[0].Path
+
+	m.dialogType = DialogNone
+	m.successMsg = "Deleting marked worktrees..."
+	return m, bulkCmd("Delete worktrees", m.state.MarkedWorktrees(), func(wt state.Worktree) (string, string, error) {
+		if wt.Path == mainPath {
+			return "", "", fmt.Errorf("the main worktree cannot be deleted")
+		}
+		if wt.Locked {
+			return "", "", fmt.Errorf("worktree is locked, skipped - unlock it with 'L' first")
+		}
+		if err := git.RemoveWorktree(repoPath, wt.Path); err != nil {
+			return "", "", err
+		}
+		if err := git.DeleteBranch(repoPath, wt.Branch); err != nil {
+			return "", "", err
+		}
+		_ = config.DeleteWorktreeNotes(repoName, wt.Name)
+		return "deleted", "", nil
+	})
+}
+
+// toggleLock locks the target worktrees, or unlocks them if all of them are locked
+func (m Model) toggleLock() (tea.Model, tea.Cmd) {
+	repo := m.state.GetSelectedRepo()
+	targets := m.state.TargetWorktrees()
+
+	unlock := true
+	for _, wt := range targets {
+		if !wt.Locked {
+			unlock = false
+			break
+		}
+	}
+	lock := func(wt state.Worktree) (string, string, error) {
+		if unlock {
+			return "unlocked", "", git.UnlockWorktree(repo.Path, wt.Path)
+		}
+		if wt.Locked {
+			return "already locked", "", nil
+		}
+		return "locked", "", git.LockWorktree(repo.Path, wt.Path)
+	}
+
+	// A single unmarked worktree is reported in the status line
+	if len(m.state.MarkedWorktrees()) == 0 {
+		detail, _, err := lock(targets[0])
+		m, cmd := m.reloadWorktrees()
+		if err != nil {
+			return m, tea.Batch(cmd, showError(err.Error()))
+		}
+		m.errorMsg = ""
+		return m, tea.Batch(cmd, showSuccess(fmt.Sprintf("'%s' %s", targets[0].Name, detail)))
+	}
+
+	title := "Lock worktrees"
+	if unlock {
+		title = "Unlock worktrees"
+	}
+	return m, bulkCmd(title, targets, lock)
+}
+
+// fetchTargets fetches the remotes of the target worktrees
+func (m Model) fetchTargets() (tea.Model, tea.Cmd) {
+	targets := m.state.TargetWorktrees()
+	m.errorMsg = ""
+	m.successMsg = fmt.Sprintf("Fetching %d worktree(s)...", len(targets))
+	return m, bulkCmd("Fetch", targets, func(wt state.Worktree) (string, string, error) {
+		output, err := git.FetchWorktree(wt.Path)
+		return "fetched", output, err
+	})
+}
+
+// fetchAll fetches the remotes of all worktrees of the selected repository
+func (m Model) fetchAll() (tea.Model, tea.Cmd) {
+	worktrees := m.state.Worktrees
+	m.errorMsg = ""
+	m.successMsg = fmt.Sprintf("Fetching %d worktree(s)...", len(worktrees))
+	return m, bulkCmd("Fetch all", worktrees, func(wt state.Worktree) (string, string, error) {
+		output, err := git.FetchWorktree(wt.Path)
+		return "fetched", output, err
+	})
+}
+
+// preparedRun is a command created on the Update goroutine, from the config and the selected
+// repository at that time, to be run in the background
+type preparedRun struct {
+	cmd *exec.Cmd
+	run runs.Run
+	err error
+}
+
+// start starts the command without waiting for it; the run log records its output and exit status
+func (p preparedRun) start(runLog *runs.Log) error {
+	if p.err != nil {
+		return p.err
+	}
+	if _, err := runLog.Start(p.run, p.cmd); err != nil {
+		return fmt.Errorf("failed to execute script: %w", err)
+	}
+	return nil
+}
+
+// runScriptOnMarked starts the enter script for each marked worktree
+func (m Model) runScriptOnMarked() (tea.Model, tea.Cmd) {
+	marked := m.state.MarkedWorktrees()
+	prepared := map[string]preparedRun{}
+	for _, wt := range marked {
+		prepared[wt.Path] = m.prepareScript(m.state.Config.EnterScript, wt)
+	}
+	runLog := m.runLog
+	return m, bulkCmd("Run script", marked, func(wt state.Worktree) (string, string, error) {
+		return "started", "", prepared[wt.Path].start(runLog)
+	})
+}
+
+// yankMarked copies the yank template of all marked worktrees, one per line
+func (m Model) yankMarked() (tea.Model, tea.Cmd) {
+	repo := m.state.GetSelectedRepo()
+	marked := m.state.MarkedWorktrees()
+
+	lines := make([]string, len(marked))
+	for i, wt := range marked {
+		text, err := m.yankText(repo, wt)
+		if err != nil {
+			return m, showError(fmt.Sprintf("Failed to apply yank_template: %v", err))
+		}
+		lines[i] = text
+	}
+	if err := clipboard.WriteAll(strings.Join(lines, "\n")); err != nil {
+		return m, showError(fmt.Sprintf("Failed to copy: %v", err))
+	}
+
+	m.errorMsg = ""
+	return m, showSuccess(fmt.Sprintf("Copied %d commands to clipboard", len(marked)))
+}
@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/michael-rose/workman/internal/config"
+	"github.com/michael-rose/workman/internal/state"
+)
+
+func TestDeleteMarkedSkipsLocked(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	s := state.New(&config.Config{Repositories: []config.Repository{{Name: "repo", Path: dir}}})
+	m := NewModel(s, DefaultKeyMap())
+	s.Worktrees = []state.Worktree{
+		{Name: "main", Branch: "main", Path: dir},
+		{Name: "kept", Branch: "kept", Path: dir + "/kept", Locked: true},
+	}
+	s.Marked[dir+"/kept"] = true
+
+	_, cmd := m.deleteMarkedWorktrees()
+	report := cmd().(reportMsg)
+	if !report.failed || !strings.Contains(report.output, "✗ kept: worktree is locked, skipped") {
+		t.Errorf("report = %q", report.output)
+	}
+}
+
+func TestRunScriptOnMarkedUsesRepoAtStart(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	script := filepath.Join(dir, "enter.sh")
+	if err := os.WriteFile(script, []byte("printf %s \"$WORKMAN_REPO\" > \"$WORKMAN_WORKTREE_PATH/repo.txt\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{
+		EnterScript:  script,
+		Repositories: []config.Repository{{Name: "first", Path: dir}, {Name: "second", Path: dir}},
+	}
+	s := state.New(cfg)
+	m := NewModel(s, DefaultKeyMap())
+	s.Worktrees = []state.Worktree{{Name: "main", Branch: "main", Path: dir}}
+	s.Marked[dir] = true
+
+	_, cmd := m.runScriptOnMarked()
+	// Selecting another repository before the command runs does not affect it
+	s.SelectedRepoIndex = 1
+	if report := cmd().(reportMsg); report.failed {
+		t.Fatalf("report = %q", report.output)
+	}
+	select {
+	case <-m.runLog.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("script did not finish")
+	}
+	if content, _ := os.ReadFile(filepath.Join(dir, "repo.txt")); string(content) != "first" {
+		t.Errorf("WORKMAN_REPO = %q", content)
+	}
+}
+
+func TestDeleteWarningsCheckedInBackground(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	s := state.New(&config.Config{Repositories: []config.Repository{{Name: "repo", Path: dir}}})
+	m := NewModel(s, DefaultKeyMap())
+	s.Worktrees = []state.Worktree{{Name: "main", Branch: "main", Path: dir, Locked: true}}
+
+	model, cmd := m.confirmDeleteWorktree()
+	m = model.(Model)
+	if !m.confirmDeleteDialog.Checking() || !strings.Contains(m.confirmDeleteDialog.View(), "Checking") {
+		t.Fatalf("dialog not checking:\n%s", m.confirmDeleteDialog.View())
+	}
+
+	// Deleting waits for the check
+	model, _ = m.handleDialogKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if model.(Model).dialogType != DialogConfirmDelete {
+		t.Fatal("delete confirmed while checking")
+	}
+
+	model, _ = m.Update(cmd())
+	m = model.(Model)
+	if m.confirmDeleteDialog.Checking() || !strings.Contains(m.confirmDeleteDialog.View(), "Worktree is locked") {
+		t.Errorf("warnings not shown:\n%s", m.confirmDeleteDialog.View())
+	}
+
+	// Results of a check for other worktrees are ignored
+	m.confirmDeleteDialog.warnings = nil
+	model, _ = m.Update(deleteWarningsMsg{key: "other", warnings: []string{"stale"}})
+	if len(model.(Model).confirmDeleteDialog.warnings) != 0 {
+		t.Error("warnings of another check shown")
+	}
+}
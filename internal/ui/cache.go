@@ -0,0 +1,302 @@
+package ui
+
+import (
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/michael-rose/workman/internal/config"
+	"github.com/michael-rose/workman/internal/git"
+	"github.com/michael-rose/workman/internal/state"
+	"github.com/michael-rose/workman/internal/watch"
+)
+
+// loadWorkers limits the number of background loads running git at the same time
+const loadWorkers = 4
+
+// loadSlots is the worker pool shared by all background load commands
+var loadSlots = make(chan struct{}, loadWorkers)
+
+// repoLoadedMsg is sent when the data of a repository has been loaded in the background
+type repoLoadedMsg struct {
+	name string
+	data state.RepoData
+}
+
+// worktreeLoadedMsg is sent when the data of a worktree has been loaded in the background
+type worktreeLoadedMsg struct {
+	path string
+	data state.WorktreeData
+}
+
+// withLoadSlot runs fn once a slot of the worker pool is free
+func withLoadSlot(fn func() tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		loadSlots <- struct{}{}
+		defer func() { <-loadSlots }()
+		return fn()
+	}
+}
+
+// loadRepoData lists the worktrees and checks the post-create script of a repository
+func loadRepoData(repo config.Repository) state.RepoData {
+	data := state.RepoData{Worktrees: []state.Worktree{}, LoadedAt: time.Now()}
+	data.HasScript, _ = config.HasRepoScript(repo.Name)
+
+	// Only try to load worktrees for local repos or if path exists
+	if _, err := os.Stat(repo.Path); os.IsNotExist(err) {
+		return data
+	}
+
+	worktrees, err := git.ListWorktrees(repo.Path)
+	if err != nil {
+		data.Err = err
+		return data
+	}
+	data.Worktrees = worktrees
+	return data
+}
+
+// loadWorktreeData loads submodules, notes and, if requested, the details of a worktree
+func loadWorktreeData(repo config.Repository, wt state.Worktree, withDetails bool) state.WorktreeData {
+	data := state.WorktreeData{Submodules: []state.Submodule{}, LoadedAt: time.Now()}
+	data.Notes, _ = config.GetWorktreeNotes(repo.Name, wt.Name)
+	if submodules, err := git.ListSubmodules(wt.Path); err == nil {
+		data.Submodules = submodules
+	}
+
+	if withDetails {
+		baseBranch, _ := git.ResolveBaseBranch(repo.Path, repo.BaseBranch, repo.Type == "remote")
+		if details, err := git.GetWorktreeDetails(wt.Path, baseBranch, recentCommitCount); err == nil {
+			data.Details = &details
+		}
+	}
+	return data
+}
+
+// refreshRepoCmd reloads a repository in the background if its cache entry is missing or stale
+func (m Model) refreshRepoCmd(repo config.Repository, force bool) tea.Cmd {
+	if !m.state.Cache.StartRepoLoad(repo.Name, force) {
+		return nil
+	}
+	return withLoadSlot(func() tea.Msg {
+		return repoLoadedMsg{name: repo.Name, data: loadRepoData(repo)}
+	})
+}
+
+// refreshWorktreeCmd reloads a worktree in the background if its cache entry is missing or stale
+func (m Model) refreshWorktreeCmd(repo config.Repository, wt state.Worktree, force bool) tea.Cmd {
+	if !m.state.Cache.StartWorktreeLoad(wt.Path, force) {
+		return nil
+	}
+	withDetails := m.state.ShowDetails
+	return withLoadSlot(func() tea.Msg {
+		return worktreeLoadedMsg{path: wt.Path, data: loadWorktreeData(repo, wt, withDetails)}
+	})
+}
+
+// refreshAllCmd reloads all repositories and the selected worktree in the background
+func (m Model) refreshAllCmd(force bool) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, repo := range m.state.Config.Repositories {
+		cmds = append(cmds, m.refreshRepoCmd(repo, force))
+	}
+	if repo := m.state.GetSelectedRepo(); repo != nil && m.state.SelectedWTIndex < len(m.state.Worktrees) {
+		cmds = append(cmds, m.refreshWorktreeCmd(*repo, m.state.Worktrees[m.state.SelectedWTIndex], force))
+	}
+	return tea.Batch(cmds...)
+}
+
+// cacheWorktrees updates the cached worktree list of a repository after the worktrees were changed
+func (m Model) cacheWorktrees(repoName string, worktrees []state.Worktree) {
+	data, _ := m.state.Cache.Repo(repoName)
+	data.Worktrees = worktrees
+	data.Err = nil
+	data.LoadedAt = time.Now()
+	m.state.Cache.SetRepo(repoName, data)
+}
+
+// showRepo shows the cached worktrees of the selected repository and revalidates them if needed
+func (m Model) showRepo() (Model, tea.Cmd) {
+	m.state.ClearMarks()
+	m.state.SelectedWTIndex = 0
+	m.state.Worktrees = []state.Worktree{}
+
+	repo := m.state.GetSelectedRepo()
+	if repo == nil {
+		m.state.Submodules = []state.Submodule{}
+		m.state.Details = nil
+		return m, nil
+	}
+
+	if data, ok := m.state.Cache.Repo(repo.Name); ok {
+		m.state.Worktrees = data.Worktrees
+	}
+	m, cmd := m.showWorktree()
+	return m, tea.Batch(cmd, m.refreshRepoCmd(*repo, false))
+}
+
+// showWorktree shows the cached data of the selected worktree and revalidates it if needed
+func (m Model) showWorktree() (Model, tea.Cmd) {
+	m.state.Submodules = []state.Submodule{}
+	m.state.Notes = ""
+	m.state.Details = nil
+
+	repo := m.state.GetSelectedRepo()
+	if repo == nil || m.state.SelectedWTIndex >= len(m.state.Worktrees) {
+		return m, nil
+	}
+	selectedWT := m.state.Worktrees[m.state.SelectedWTIndex]
+
+	data, ok := m.state.Cache.Worktree(selectedWT.Path)
+	if ok {
+		m.state.Submodules = data.Submodules
+		m.state.Notes = data.Notes
+		m.state.Details = data.Details
+	}
+	// Details were not loaded while hidden
+	force := ok && m.state.ShowDetails && data.Details == nil
+	return m, m.refreshWorktreeCmd(*repo, selectedWT, force)
+}
+
+// handleRepoLoaded caches a loaded repository and shows it if it is selected
+func (m Model) handleRepoLoaded(msg repoLoadedMsg) (tea.Model, tea.Cmd) {
+	reload := m.state.Cache.SetRepo(msg.name, msg.data)
+
+	var watchCmd tea.Cmd
+	for _, repo := range m.state.Config.Repositories {
+		if repo.Name == msg.name {
+			watchCmd = m.watchRepoCmd(repo, msg.data.Worktrees)
+			if reload {
+				watchCmd = tea.Batch(watchCmd, m.refreshRepoCmd(repo, true))
+			}
+		}
+	}
+
+	repo := m.state.GetSelectedRepo()
+	if repo == nil || repo.Name != msg.name {
+		return m, watchCmd
+	}
+
+	// Keep the selection on the same worktree
+	var selectedPath string
+	if m.state.SelectedWTIndex < len(m.state.Worktrees) {
+		selectedPath = m.state.Worktrees[m.state.SelectedWTIndex].Path
+	}
+	m.state.Worktrees = msg.data.Worktrees
+	m.state.SelectedWTIndex = 0
+	existing := map[string]bool{}
+	for i, wt := range m.state.Worktrees {
+		existing[wt.Path] = true
+		if wt.Path == selectedPath {
+			m.state.SelectedWTIndex = i
+		}
+	}
+	for path := range m.state.Marked {
+		if !existing[path] {
+			delete(m.state.Marked, path)
+		}
+	}
+
+	m, cmd := m.showWorktree()
+	return m, tea.Batch(cmd, watchCmd)
+}
+
+// handleWorktreeLoaded caches a loaded worktree and shows it if it is selected
+func (m Model) handleWorktreeLoaded(msg worktreeLoadedMsg) (tea.Model, tea.Cmd) {
+	reload := m.state.Cache.SetWorktree(msg.path, msg.data)
+
+	// Other worktrees are reloaded when shown, as their data is stored as stale
+	repo := m.state.GetSelectedRepo()
+	if repo != nil && m.state.SelectedWTIndex < len(m.state.Worktrees) && m.state.Worktrees[m.state.SelectedWTIndex].Path == msg.path {
+		m.state.Submodules = msg.data.Submodules
+		m.state.Notes = msg.data.Notes
+		m.state.Details = msg.data.Details
+		if reload {
+			return m, m.refreshWorktreeCmd(*repo, m.state.Worktrees[m.state.SelectedWTIndex], true)
+		}
+	}
+	return m, nil
+}
+
+// refreshWorktree shows the cached data of the selected worktree and reloads it in the
+// background, after an operation changed it
+func (m Model) refreshWorktree() (Model, tea.Cmd) {
+	if m.state.SelectedWTIndex < len(m.state.Worktrees) {
+		m.state.Cache.ExpireWorktree(m.state.Worktrees[m.state.SelectedWTIndex].Path)
+	}
+	return m.showWorktree()
+}
+
+// reloadWorktrees reloads the worktree list of the selected repository and the selected worktree
+// in the background; the selection and the marks of worktrees that still exist are kept
+func (m Model) reloadWorktrees() (Model, tea.Cmd) {
+	repo := m.state.GetSelectedRepo()
+	if repo == nil {
+		return m, nil
+	}
+	m, cmd := m.refreshWorktree()
+	return m, tea.Batch(cmd, m.refreshRepoCmd(*repo, true))
+}
+
+// fsChangedMsg is sent when the watcher detected changes on disk
+type fsChangedMsg struct {
+	event watch.Event
+}
+
+// watchDebounce collects filesystem events into one refresh
+const watchDebounce = 500 * time.Millisecond
+
+// waitForChange waits for the next change detected by the watcher
+func (m Model) waitForChange() tea.Cmd {
+	if m.watcher == nil {
+		return nil
+	}
+	events := m.watcher.Events()
+	return func() tea.Msg {
+		return fsChangedMsg{event: <-events}
+	}
+}
+
+// watchRepoCmd (re)registers the watches of a repository in the background
+func (m Model) watchRepoCmd(repo config.Repository, worktrees []state.Worktree) tea.Cmd {
+	if m.watcher == nil {
+		return nil
+	}
+	watcher := m.watcher
+	return func() tea.Msg {
+		watcher.Watch(repo.Name, repo.Path, worktrees)
+		return nil
+	}
+}
+
+// handleFSChanged refreshes what changed on disk: the selected repository and worktree right away,
+// everything else when it is shown next
+func (m Model) handleFSChanged(msg fsChangedMsg) (tea.Model, tea.Cmd) {
+	cmds := []tea.Cmd{m.waitForChange()}
+	selectedRepo := m.state.GetSelectedRepo()
+	var selectedWT *state.Worktree
+	if selectedRepo != nil && m.state.SelectedWTIndex < len(m.state.Worktrees) {
+		selectedWT = &m.state.Worktrees[m.state.SelectedWTIndex]
+	}
+
+	for _, name := range msg.event.Repos {
+		if selectedRepo != nil && selectedRepo.Name == name {
+			cmds = append(cmds, m.refreshRepoCmd(*selectedRepo, true))
+			// A checkout changes the branch of the selected worktree
+			if selectedWT != nil {
+				cmds = append(cmds, m.refreshWorktreeCmd(*selectedRepo, *selectedWT, true))
+			}
+		} else {
+			m.state.Cache.ExpireRepo(name)
+		}
+	}
+	for _, path := range msg.event.Worktrees {
+		if selectedWT != nil && selectedWT.Path == path {
+			cmds = append(cmds, m.refreshWorktreeCmd(*selectedRepo, *selectedWT, true))
+		} else {
+			m.state.Cache.ExpireWorktree(path)
+		}
+	}
+	return m, tea.Batch(cmds...)
+}
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/michael-rose/workman/internal/config"
+	"github.com/michael-rose/workman/internal/state"
+)
+
+func TestNotesCachedWithWorktree(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	if err := config.SaveWorktreeNotes("repo", "main", "first note"); err != nil {
+		t.Fatal(err)
+	}
+	s := state.New(&config.Config{Repositories: []config.Repository{{Name: "repo", Path: dir}}})
+	m := NewModel(s, DefaultKeyMap())
+	s.Worktrees = []state.Worktree{{Name: "main", Branch: "main", Path: dir}}
+
+	m, cmd := m.showWorktree()
+	model, _ := m.Update(cmd())
+	m = model.(Model)
+	if m.state.Notes != "first note" {
+		t.Fatalf("notes = %q", m.state.Notes)
+	}
+
+	// Rendering uses the cached notes instead of reading them again
+	if err := config.SaveWorktreeNotes("repo", "main", "changed elsewhere"); err != nil {
+		t.Fatal(err)
+	}
+	if view := m.View(); !strings.Contains(view, "first note") {
+		t.Errorf("cached notes not shown:\n%s", view)
+	}
+
+	// A refresh picks up the change
+	m, cmd = m.refreshWorktree()
+	model, _ = m.Update(cmd())
+	if notes := model.(Model).state.Notes; notes != "changed elsewhere" {
+		t.Errorf("notes after refresh = %q", notes)
+	}
+}
+
+func TestForcedRefreshDuringLoadReloads(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	if err := config.SaveWorktreeNotes("repo", "main", "before"); err != nil {
+		t.Fatal(err)
+	}
+	s := state.New(&config.Config{Repositories: []config.Repository{{Name: "repo", Path: dir}}})
+	m := NewModel(s, DefaultKeyMap())
+	s.Worktrees = []state.Worktree{{Name: "main", Branch: "main", Path: dir}}
+	s.Cache.ExpireWorktree(dir)
+
+	// A load starts, then an operation changes the worktree and forces a refresh while it is in flight
+	m, load := m.showWorktree()
+	staleMsg := load()
+	if err := config.SaveWorktreeNotes("repo", "main", "after"); err != nil {
+		t.Fatal(err)
+	}
+	m, cmd := m.refreshWorktree()
+	if cmd != nil {
+		t.Fatal("Expected no second load while one is in flight")
+	}
+
+	// The stale result is shown but loaded again
+	model, cmd := m.Update(staleMsg)
+	m = model.(Model)
+	if cmd == nil {
+		t.Fatal("Expected another load after the stale result")
+	}
+	model, _ = m.Update(cmd())
+	if notes := model.(Model).state.Notes; notes != "after" {
+		t.Errorf("notes after reload = %q", notes)
+	}
+}
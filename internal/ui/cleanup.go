@@ -0,0 +1,238 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/michael-rose/workman/internal/config"
+	"github.com/michael-rose/workman/internal/git"
+)
+
+// CleanupView lists branches that are merged, have a gone upstream or are stale and lets the user remove them
+type CleanupView struct {
+	repoName    string
+	baseBranch  string
+	staleDays   int
+	candidates  []git.CleanupCandidate
+	checked     map[int]bool
+	selected    int
+	confirmMode bool
+}
+
+func NewCleanupView(repoName, baseBranch string, staleDays int, candidates []git.CleanupCandidate) CleanupView {
+	return CleanupView{
+		repoName:   repoName,
+		baseBranch: baseBranch,
+		staleDays:  staleDays,
+		candidates: candidates,
+		checked:    map[int]bool{},
+	}
+}
+
+// Checked returns the candidates marked for removal
+func (v *CleanupView) Checked() []git.CleanupCandidate {
+	var result []git.CleanupCandidate
+	for i, candidate := range v.candidates {
+		if v.checked[i] {
+			result = append(result, candidate)
+		}
+	}
+	return result
+}
+
+// removable returns the candidates that can be selected for removal
+func (v *CleanupView) removable() []git.CleanupCandidate {
+	var result []git.CleanupCandidate
+	for _, candidate := range v.candidates {
+		if !candidate.Dirty {
+			result = append(result, candidate)
+		}
+	}
+	return result
+}
+
+// Confirming reports whether the view waits for the removal to be confirmed
+func (v *CleanupView) Confirming() bool {
+	return v.confirmMode
+}
+
+func (v *CleanupView) Update(msg tea.Msg) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+
+	if v.confirmMode {
+		// Any key other than "y" (handled by the model) cancels
+		v.confirmMode = false
+		return nil
+	}
+
+	switch keyMsg.String() {
+	case "up", "k":
+		if v.selected > 0 {
+			v.selected--
+		}
+	case "down", "j":
+		if v.selected < len(v.candidates)-1 {
+			v.selected++
+		}
+	case " ":
+		// Worktrees with uncommitted changes cannot be selected
+		if len(v.candidates) > 0 && !v.candidates[v.selected].Dirty {
+			v.checked[v.selected] = !v.checked[v.selected]
+		}
+	case "a":
+		// Select all, or clear the selection if everything is selected already
+		all := len(v.Checked()) == len(v.removable())
+		for i, candidate := range v.candidates {
+			v.checked[i] = !all && !candidate.Dirty
+		}
+	case "enter", "x":
+		if len(v.Checked()) > 0 {
+			v.confirmMode = true
+		}
+	}
+
+	return nil
+}
+
+func (v *CleanupView) View() string {
+	var b strings.Builder
+
+	b.WriteString(headerStyle.Render(fmt.Sprintf("Cleanup - %s", v.repoName)))
+	b.WriteString("\n")
+	criteria := fmt.Sprintf("Branches merged into %s or with a gone upstream", v.baseBranch)
+	if v.staleDays > 0 {
+		criteria += fmt.Sprintf(", or without commits for %d days", v.staleDays)
+	}
+	b.WriteString(infoStyle.Render(criteria))
+	b.WriteString("\n\n")
+
+	if len(v.candidates) == 0 {
+		b.WriteString(infoStyle.Render("  Nothing to clean up"))
+		b.WriteString("\n")
+	}
+	for i, candidate := range v.candidates {
+		check := "[ ]"
+		if v.checked[i] {
+			check = "[x]"
+		} else if candidate.Dirty {
+			check = "[-]"
+		}
+		location := "no worktree"
+		if candidate.WorktreeName != "" {
+			location = candidate.WorktreeName
+		}
+		text := fmt.Sprintf("%s %s (%s)  %s  %s", check, candidate.Branch, location,
+			strings.Join(candidate.Reasons, ", "), formatAge(time.Since(candidate.LastCommit)))
+		if candidate.Dirty {
+			text += "  uncommitted changes, kept"
+		}
+		if i == v.selected {
+			b.WriteString(selectedItemStyle.Render("> " + text))
+		} else {
+			b.WriteString(itemStyle.Render("  " + text))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	if v.confirmMode {
+		b.WriteString(itemStyle.Render(fmt.Sprintf("Remove %d branch(es) with their worktrees and notes? This cannot be undone.", len(v.Checked()))))
+		b.WriteString("\n")
+		b.WriteString(helpLine(confirmHint, anyCancelHint))
+	} else {
+		b.WriteString(helpLine(cleanupSelectHint, cleanupSelectAllHint, cleanupRemoveHint, closeHint))
+	}
+
+	dialogStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(1, 2).
+		Width(90)
+
+	return dialogStyle.Render(b.String())
+}
+
+// openCleanupView finds cleanup candidates of the selected repository
+func (m Model) openCleanupView() (tea.Model, tea.Cmd) {
+	repo := m.state.GetSelectedRepo()
+	baseBranch, err := git.ResolveBaseBranch(repo.Path, repo.BaseBranch, repo.Type == "remote")
+	if err != nil {
+		return m, showError(fmt.Sprintf("Failed to determine base branch: %v", err))
+	}
+
+	candidates, err := git.FindCleanupCandidates(repo.Path, baseBranch, m.state.Worktrees, m.state.Config.StaleDays)
+	if err != nil {
+		return m, showError(fmt.Sprintf("Failed to find branches to clean up: %v", err))
+	}
+
+	m.cleanupView = NewCleanupView(repo.Name, baseBranch, m.state.Config.StaleDays, candidates)
+	m.dialogType = DialogCleanup
+	m.errorMsg = ""
+	m.successMsg = ""
+	return m, nil
+}
+
+// removeCleanupCandidates removes the worktree, branch and notes of each checked candidate
+func (m Model) removeCleanupCandidates() (tea.Model, tea.Cmd) {
+	repo := m.state.GetSelectedRepo()
+	var removed int
+	var errors []string
+	for _, candidate := range m.cleanupView.Checked() {
+		if candidate.WorktreePath != "" {
+			// Changes may have been made since the list was shown; removing would lose them
+			if dirty, err := git.HasUncommittedChanges(candidate.WorktreePath); err != nil || dirty {
+				errors = append(errors, fmt.Sprintf("%s: worktree has uncommitted changes, skipped", candidate.Branch))
+				continue
+			}
+			if err := git.RemoveWorktree(repo.Path, candidate.WorktreePath); err != nil {
+				errors = append(errors, fmt.Sprintf("%s: %v", candidate.Branch, err))
+				continue
+			}
+		}
+		if err := git.DeleteBranch(repo.Path, candidate.Branch); err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", candidate.Branch, err))
+			continue
+		}
+		if candidate.WorktreeName != "" {
+			_ = config.DeleteWorktreeNotes(repo.Name, candidate.WorktreeName)
+		}
+		removed++
+	}
+
+	m.dialogType = DialogNone
+	m, loadCmd := m.reloadWorktrees()
+
+	if len(errors) > 0 {
+		m.successMsg = ""
+		return m, tea.Batch(loadCmd, showError(fmt.Sprintf("Removed %d branch(es), failed %d:\n%s", removed, len(errors), strings.Join(errors, "\n"))))
+	}
+	m.errorMsg = ""
+	return m, tea.Batch(loadCmd, showSuccess(fmt.Sprintf("Removed %d branch(es)", removed)))
+}
+
+func (m Model) handleCleanupKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "y":
+		if m.cleanupView.Confirming() {
+			return m.removeCleanupCandidates()
+		}
+	case "esc", "q":
+		if !m.cleanupView.Confirming() {
+			m.dialogType = DialogNone
+			m.errorMsg = ""
+			return m, nil
+		}
+	}
+
+	m.errorMsg = ""
+	cmd := m.cleanupView.Update(msg)
+	return m, cmd
+}
@@ -0,0 +1,288 @@
+package ui
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/michael-rose/workman/internal/config"
+	"github.com/michael-rose/workman/internal/state"
+)
+
+// actionFinishedMsg is sent when a foreground action exits
+type actionFinishedMsg struct {
+	name string
+	err  error
+}
+
+// namedActions returns the [[actions]] of the selected repository
+func (m Model) namedActions() []config.Action {
+	return m.state.Config.ActionsFor(m.state.GetSelectedRepo())
+}
+
+// launchChoices lists what Enter offers: the enter script, if configured, and the named actions
+func (m Model) launchChoices() []config.Action {
+	var choices []config.Action
+	if m.state.Config.EnterScript != "" {
+		choices = append(choices, config.Action{Name: "Enter script", Script: m.state.Config.EnterScript})
+	}
+	return append(choices, m.namedActions()...)
+}
+
+// launch runs what Enter is configured to do on the target worktrees
+// With named actions a picker is shown, unless there is only one thing to run
+func (m Model) launch() (tea.Model, tea.Cmd) {
+	choices := m.launchChoices()
+	switch {
+	case len(m.namedActions()) == 0:
+		// Without named actions Enter runs the enter script
+		if len(m.state.MarkedWorktrees()) > 0 {
+			return m.runScriptOnMarked()
+		}
+		return m.openWorktree(), nil
+	case len(choices) == 1:
+		return m.runAction(choices[0])
+	}
+
+	m.actionPicker = NewActionPicker(choices, len(m.state.TargetWorktrees()))
+	m.dialogType = DialogActionPicker
+	m.errorMsg = ""
+	m.successMsg = ""
+	return m, nil
+}
+
+// actionCommand creates the command of an action for a worktree, running in the worktree directory
+func actionCommand(cfg *config.Config, a config.Action, repo *config.Repository, wt state.Worktree) (*exec.Cmd, error) {
+	cmd := shellCommand(cfg, a.Command, repo, wt)
+	if a.Script != "" {
+		var err error
+		if cmd, err = scriptFileCommand(cfg, a.Script, repo, wt); err != nil {
+			return nil, err
+		}
+	}
+	cmd.Dir = wt.Path
+	return cmd, nil
+}
+
+// runAction runs a named action on the marked worktrees, or the selected one
+func (m Model) runAction(a config.Action) (tea.Model, tea.Cmd) {
+	repo := m.state.GetSelectedRepo()
+	targets := m.state.TargetWorktrees()
+	runLog := m.runLog
+	m.errorMsg = ""
+	m.successMsg = ""
+
+	switch a.RunMode() {
+	case config.ActionCaptured:
+		// The commands are created here: config and selection must not be read in the background
+		prepared := map[string]preparedRun{}
+		for _, wt := range targets {
+			cmd, err := actionCommand(m.state.Config, a, repo, wt)
+			prepared[wt.Path] = preparedRun{cmd: cmd, run: newRun(a.Name, a.Text(), repo, wt), err: err}
+		}
+		m.successMsg = fmt.Sprintf("Running %s in %d worktree(s)...", a.Name, len(targets))
+		return m, bulkCmd(a.Name, targets, func(wt state.Worktree) (string, string, error) {
+			p := prepared[wt.Path]
+			if p.err != nil {
+				return "", "", p.err
+			}
+			output, err := runLog.Run(p.run, p.cmd)
+			if err != nil {
+				return "", string(output), fmt.Errorf("%s failed: %w", a.Name, err)
+			}
+			return "done", string(output), nil
+		})
+
+	case config.ActionForeground:
+		// Run one worktree after the other, each taking over the terminal
+		var cmds []tea.Cmd
+		for _, wt := range targets {
+			cmd, err := actionCommand(m.state.Config, a, repo, wt)
+			if err != nil {
+				return m, showError(fmt.Sprintf("Failed to run %s: %v", a.Name, err))
+			}
+			// The output goes to the terminal, only the exit status is recorded
+			done := runLog.Begin(newRun(a.Name, a.Text(), repo, wt), cmd)
+			cmds = append(cmds, tea.ExecProcess(cmd, func(err error) tea.Msg {
+				done(err)
+				return actionFinishedMsg{name: a.Name, err: err}
+			}))
+		}
+		return m, tea.Sequence(cmds...)
+
+	default:
+		for _, wt := range targets {
+			cmd, err := actionCommand(m.state.Config, a, repo, wt)
+			if err == nil {
+				// The run log waits for the process and reports failures
+				_, err = runLog.Start(newRun(a.Name, a.Text(), repo, wt), cmd)
+			}
+			if err != nil {
+				return m, showError(fmt.Sprintf("Failed to run %s in %s: %v", a.Name, wt.Name, err))
+			}
+		}
+		m.successMsg = fmt.Sprintf("Started %s", a.Name)
+		if len(targets) > 1 {
+			m.successMsg += fmt.Sprintf(" in %d worktrees", len(targets))
+		}
+		return m, nil
+	}
+}
+
+// runActionKey runs the named action bound to a key, if any
+func (m Model) runActionKey(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
+	if !worktreeSelected(m) {
+		return m, nil, false
+	}
+	for _, a := range m.namedActions() {
+		if a.Key != "" && msg.String() == normalizeKey(a.Key) {
+			model, cmd := m.runAction(a)
+			return model, cmd, true
+		}
+	}
+	return m, nil, false
+}
+
+// actionBindings returns the bindings of the named actions with keys, for the help
+func (m Model) actionBindings() []key.Binding {
+	var bindings []key.Binding
+	for _, a := range m.namedActions() {
+		if a.Key != "" {
+			bindings = append(bindings, binding(a.Name, normalizeKey(a.Key)))
+		}
+	}
+	return bindings
+}
+
+// ValidateActionKeys reports keys of named actions that are bound to a main view action
+// or to another named action of the same repository
+func ValidateActionKeys(keys KeyMap, cfg *config.Config) error {
+	owners := map[string]string{}
+	for _, action := range keys.actions() {
+		if !action.binding.Enabled() {
+			continue
+		}
+		for _, k := range action.binding.Keys() {
+			owners[k] = action.name
+		}
+	}
+
+	check := func(actions []config.Action) error {
+		used := map[string]string{}
+		for _, a := range actions {
+			if a.Key == "" {
+				continue
+			}
+			k := normalizeKey(a.Key)
+			if owner, ok := owners[k]; ok {
+				return fmt.Errorf("key '%s' of action '%s' is bound to '%s'", displayKey(k), a.Name, owner)
+			}
+			if other, ok := used[k]; ok {
+				return fmt.Errorf("key '%s' is bound to both actions '%s' and '%s'", displayKey(k), other, a.Name)
+			}
+			used[k] = a.Name
+		}
+		return nil
+	}
+
+	if err := check(cfg.ActionsFor(nil)); err != nil {
+		return err
+	}
+	for i := range cfg.Repositories {
+		if err := check(cfg.ActionsFor(&cfg.Repositories[i])); err != nil {
+			return fmt.Errorf("repository '%s': %w", cfg.Repositories[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// ActionPicker lets the user choose which action Enter runs
+type ActionPicker struct {
+	actions  []config.Action
+	targets  int // number of worktrees the action runs on
+	selected int
+}
+
+func NewActionPicker(actions []config.Action, targets int) ActionPicker {
+	return ActionPicker{actions: actions, targets: targets}
+}
+
+// Selected returns the selected action
+func (p *ActionPicker) Selected() config.Action {
+	return p.actions[p.selected]
+}
+
+func (p *ActionPicker) Update(msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "up", "k":
+			p.selected = (p.selected - 1 + len(p.actions)) % len(p.actions)
+		case "down", "j":
+			p.selected = (p.selected + 1) % len(p.actions)
+		}
+	}
+	return nil
+}
+
+func (p *ActionPicker) View() string {
+	var b strings.Builder
+
+	title := "Run action"
+	if p.targets > 1 {
+		title += fmt.Sprintf(" on %d worktrees", p.targets)
+	}
+	b.WriteString(headerStyle.Render(title))
+	b.WriteString("\n\n")
+
+	for i, a := range p.actions {
+		text := fmt.Sprintf("%d  %s", i+1, a.Name)
+		if i >= 9 {
+			text = "   " + a.Name
+		}
+		details := a.RunMode()
+		if a.Key != "" {
+			details = displayKey(normalizeKey(a.Key)) + " · " + details
+		}
+		text += "  " + infoStyle.Render(details)
+		if i == p.selected {
+			b.WriteString(selectedItemStyle.Render("> " + text))
+		} else {
+			b.WriteString(itemStyle.Render("  " + text))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(helpLine(navigateHint, actionRunHint, actionNumberHint, cancelHint))
+
+	dialogStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(1, 2)
+
+	return dialogStyle.Render(b.String())
+}
+
+func (m Model) handleActionPickerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch s := msg.String(); s {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q":
+		m.dialogType = DialogNone
+		return m, nil
+	case "enter":
+		m.dialogType = DialogNone
+		return m.runAction(m.actionPicker.Selected())
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		if index := int(s[0] - '1'); index < len(m.actionPicker.actions) {
+			m.dialogType = DialogNone
+			return m.runAction(m.actionPicker.actions[index])
+		}
+		return m, nil
+	}
+
+	cmd := m.actionPicker.Update(msg)
+	return m, cmd
+}
@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/michael-rose/workman/internal/config"
+	"github.com/michael-rose/workman/internal/state"
+)
+
+func TestValidateActionKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Config
+		wantErr string
+	}{
+		{name: "free keys", cfg: config.Config{Actions: []config.Action{{Name: "tests", Key: "T"}, {Name: "tmux", Key: "ctrl+t"}}}},
+		{name: "bound to main view", cfg: config.Config{Actions: []config.Action{{Name: "tests", Key: "d"}}}, wantErr: "key 'd' of action 'tests' is bound to 'diff'"},
+		{name: "two actions", cfg: config.Config{Actions: []config.Action{{Name: "a", Key: "T"}, {Name: "b", Key: "T"}}}, wantErr: "both actions 'a' and 'b'"},
+		{name: "repository override", cfg: config.Config{
+			Actions:      []config.Action{{Name: "a", Key: "T"}},
+			Repositories: []config.Repository{{Name: "r", Actions: []config.Action{{Name: "b", Key: "T", Command: "x"}}}},
+		}, wantErr: "repository 'r'"},
+		{name: "replaced in repository", cfg: config.Config{
+			Actions:      []config.Action{{Name: "a", Key: "T"}},
+			Repositories: []config.Repository{{Name: "r", Actions: []config.Action{{Name: "a", Key: "T", Command: "x"}}}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateActionKeys(DefaultKeyMap(), &tt.cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLaunchNamedActions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	cfg := &config.Config{
+		Repositories: []config.Repository{{Name: "repo", Path: dir}},
+		Actions: []config.Action{
+			{Name: "touch", Key: "T", Command: "touch touched"},
+			{Name: "tests", Command: "echo $WORKMAN_BRANCH ok", Mode: config.ActionCaptured},
+		},
+	}
+	s := state.New(cfg)
+	m := NewModel(s, DefaultKeyMap())
+	s.ActivePane = state.WorktreesPane
+	s.Worktrees = []state.Worktree{{Name: "main", Branch: "main", Path: dir}}
+
+	// Enter shows a picker with the named actions
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(Model)
+	if m.dialogType != DialogActionPicker || len(m.actionPicker.actions) != 2 {
+		t.Fatalf("dialog %v with %d actions", m.dialogType, len(m.actionPicker.actions))
+	}
+
+	// Captured actions report their output
+	model, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'2'}})
+	m = model.(Model)
+	msg, ok := cmd().(reportMsg)
+	if !ok || msg.failed || !strings.Contains(msg.output, "main ok") {
+		t.Fatalf("report = %+v", msg)
+	}
+
+	// Action keys run detached actions in the worktree
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'T'}})
+	if model.(Model).errorMsg != "" {
+		t.Fatalf("error: %s", model.(Model).errorMsg)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "touched")); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("detached action did not run in the worktree")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/michael-rose/workman/internal/state"
+)
+
+// recentCommitCount is the number of commits shown in the details section
+const recentCommitCount = 5
+
+// renderDetails renders HEAD, upstream, base branch and activity of the selected worktree
+func (m Model) renderDetails(details state.WorktreeDetails) string {
+	detailsHeader := sectionStyle.Render("\nDetails:")
+
+	var lines []string
+	if details.Head.SHA != "" {
+		lines = append(lines, fmt.Sprintf("HEAD:          %s %s", details.Head.SHA, details.Head.Subject))
+	}
+
+	if details.Upstream != "" {
+		lines = append(lines, fmt.Sprintf("Upstream:      %s (%s)", details.Upstream, formatAheadBehind(details.Ahead, details.Behind)))
+	} else {
+		lines = append(lines, "Upstream:      none")
+	}
+
+	if details.BaseBranch != "" {
+		lines = append(lines, fmt.Sprintf("Base:          %s (%s)", details.BaseBranch, formatAheadBehind(details.AheadBase, details.BehindBase)))
+	}
+
+	if details.Changes > 0 {
+		lines = append(lines, fmt.Sprintf("Changes:       %d uncommitted", details.Changes))
+	} else {
+		lines = append(lines, "Changes:       clean")
+	}
+
+	if !details.Head.Date.IsZero() {
+		lines = append(lines, fmt.Sprintf("Last commit:   %s", formatTimestamp(details.Head.Date)))
+	}
+	if !details.LastModified.IsZero() {
+		lines = append(lines, fmt.Sprintf("Last modified: %s", formatTimestamp(details.LastModified)))
+	}
+
+	if len(details.RecentCommits) > 0 {
+		lines = append(lines, "Recent commits:")
+		for _, commit := range details.RecentCommits {
+			lines = append(lines, fmt.Sprintf("  %s %s", commit.SHA, commit.Subject))
+		}
+	}
+
+	for i, line := range lines {
+		lines[i] = infoStyle.Render("  " + line)
+	}
+
+	return detailsHeader + "\n" + strings.Join(lines, "\n")
+}
+
+// formatAheadBehind renders ahead/behind counts like "↑2 ↓0"
+func formatAheadBehind(ahead, behind int) string {
+	return fmt.Sprintf("↑%d ↓%d", ahead, behind)
+}
+
+// formatTimestamp renders a timestamp together with its age, e.g. "2026-01-22 08:09 (3 days ago)"
+func formatTimestamp(t time.Time) string {
+	return fmt.Sprintf("%s (%s)", t.Local().Format("2006-01-02 15:04"), formatAge(time.Since(t)))
+}
+
+// formatAge renders a duration as a coarse human readable age
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%d min ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%d hours ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%d days ago", int(d.Hours()/24))
+	}
+}
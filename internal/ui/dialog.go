@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/michael-rose/workman/internal/state"
 )
 
 type DialogType int
@@ -20,6 +21,17 @@ const (
 	DialogConfirmDeleteRepo
 	DialogEditNotes
 	DialogEditScript
+	DialogDiff
+	DialogStash
+	DialogSyncConflict
+	DialogConfirmPush
+	DialogOutput
+	DialogCleanup
+	DialogOverview
+	DialogHelp
+	DialogPalette
+	DialogActionPicker
+	DialogRuns
 )
 
 type AddRepoDialog struct {
@@ -134,7 +146,7 @@ func (d *AddRepoDialog) View() string {
 	}
 	b.WriteString("\n\n")
 
-	b.WriteString(helpStyle.Render("Enter: next field  •  Ctrl+S: save  •  Esc: cancel"))
+	b.WriteString(helpLine(nextFieldHint, saveHint, cancelHint))
 
 	dialogStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -296,7 +308,7 @@ func (d *AddWorktreeDialog) View() string {
 			if i == d.selectedSuggestion {
 				// Highlight selected suggestion
 				suggestionStyle := lipgloss.NewStyle().
-					Foreground(lipgloss.AdaptiveColor{Light: "#1F2937", Dark: "#F9FAFB"}).
+					Foreground(highlightColor).
 					Background(primaryColor).
 					Bold(true)
 				b.WriteString(suggestionStyle.Render("  > " + suggestion))
@@ -306,7 +318,7 @@ func (d *AddWorktreeDialog) View() string {
 			b.WriteString("\n")
 		}
 		b.WriteString("\n")
-		b.WriteString(helpStyle.Render("↑↓: navigate  •  Tab/Enter: select"))
+		b.WriteString(helpLine(suggestionHint, selectHint))
 	} else {
 		b.WriteString("\n")
 		// Show hint
@@ -316,7 +328,7 @@ func (d *AddWorktreeDialog) View() string {
 
 	b.WriteString("\n\n")
 
-	b.WriteString(helpStyle.Render("Ctrl+S: create  •  Esc: cancel"))
+	b.WriteString(helpLine(createHint, cancelHint))
 
 	dialogStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -355,34 +367,83 @@ func (d *AddWorktreeDialog) Reset() {
 type ConfirmDeleteDialog struct {
 	worktreeName string
 	branchName   string
+	worktrees    []state.Worktree // set when deleting the marked worktrees
+	key          string           // identifies the worktrees the warnings are checked for
+	checking     bool             // the warnings are being checked in the background
+	warnings     []string
 }
 
-func NewConfirmDeleteDialog(worktreeName, branchName string) ConfirmDeleteDialog {
+// NewConfirmDeleteDialog confirms deleting a worktree and its branch once its warnings are checked
+func NewConfirmDeleteDialog(wt state.Worktree) ConfirmDeleteDialog {
 	return ConfirmDeleteDialog{
-		worktreeName: worktreeName,
-		branchName:   branchName,
+		worktreeName: wt.Name,
+		branchName:   wt.Branch,
+		key:          deleteKey([]state.Worktree{wt}),
+		checking:     true,
+	}
+}
+
+// NewConfirmDeleteMarkedDialog confirms deleting several worktrees and their branches
+func NewConfirmDeleteMarkedDialog(worktrees []state.Worktree) ConfirmDeleteDialog {
+	return ConfirmDeleteDialog{
+		worktrees: worktrees,
+		key:       deleteKey(worktrees),
+		checking:  true,
 	}
 }
 
+// Checking reports whether the warnings are still being checked; deleting waits for them
+func (d *ConfirmDeleteDialog) Checking() bool {
+	return d.checking
+}
+
 func (d *ConfirmDeleteDialog) View() string {
 	var b strings.Builder
 
 	b.WriteString(headerStyle.Render("⚠ Confirm Delete"))
 	b.WriteString("\n\n")
 
-	warning := fmt.Sprintf("Delete worktree '%s' and branch '%s'?", d.worktreeName, d.branchName)
-	b.WriteString(itemStyle.Render(warning))
-	b.WriteString("\n\n")
+	if len(d.worktrees) > 0 {
+		b.WriteString(itemStyle.Render(fmt.Sprintf("Delete %d worktrees and their branches?", len(d.worktrees))))
+		b.WriteString("\n")
+		for _, wt := range d.worktrees {
+			b.WriteString(infoStyle.Render(fmt.Sprintf("  • %s [%s]", wt.Name, wt.Branch)))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	} else {
+		warning := fmt.Sprintf("Delete worktree '%s' and branch '%s'?", d.worktreeName, d.branchName)
+		b.WriteString(itemStyle.Render(warning))
+		b.WriteString("\n\n")
+	}
 
 	hint := infoStyle.Render("⚠ This will delete the branch even if unmerged!")
 	b.WriteString(hint)
 	b.WriteString("\n\n")
 
-	b.WriteString(helpStyle.Render("y: confirm  •  n/Esc: cancel"))
+	if d.checking {
+		b.WriteString(infoStyle.Render("Checking for submodule changes and unpushed LFS objects..."))
+		b.WriteString("\n\n")
+	}
+	if len(d.warnings) > 0 {
+		warningStyle := lipgloss.NewStyle().
+			Foreground(errorColor)
+		for _, warning := range d.warnings {
+			b.WriteString(warningStyle.Render("• " + warning))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if d.checking {
+		b.WriteString(helpLine(rejectHint))
+	} else {
+		b.WriteString(helpLine(confirmHint, rejectHint))
+	}
 
 	dialogStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.AdaptiveColor{Light: "#B91C1C", Dark: "#EF4444"}).
+		BorderForeground(errorColor).
 		Padding(1, 2).
 		Width(55)
 
@@ -423,11 +484,11 @@ func (d *ConfirmDeleteRepositoryDialog) View() string {
 	b.WriteString(hint)
 	b.WriteString("\n\n")
 
-	b.WriteString(helpStyle.Render("y: confirm  •  n/Esc: cancel"))
+	b.WriteString(helpLine(confirmHint, rejectHint))
 
 	dialogStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.AdaptiveColor{Light: "#B91C1C", Dark: "#EF4444"}).
+		BorderForeground(errorColor).
 		Padding(1, 2).
 		Width(60)
 
@@ -471,7 +532,7 @@ func (d *EditNotesDialog) View() string {
 	b.WriteString(d.textarea.View())
 	b.WriteString("\n\n")
 
-	b.WriteString(helpStyle.Render("Ctrl+S: save  •  Esc: cancel"))
+	b.WriteString(helpLine(saveHint, cancelHint))
 
 	dialogStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -529,7 +590,7 @@ func (d *EditScriptDialog) View() string {
 	b.WriteString(d.textarea.View())
 	b.WriteString("\n\n")
 
-	b.WriteString(helpStyle.Render("Ctrl+S: save  •  Esc: cancel"))
+	b.WriteString(helpLine(saveHint, cancelHint))
 
 	dialogStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
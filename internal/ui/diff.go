@@ -0,0 +1,255 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/michael-rose/workman/internal/git"
+)
+
+// diffFileListWidth is the width of the file list to the left of the diff
+const diffFileListWidth = 32
+
+// DiffView shows the changes of a worktree or a stash in a scrollable viewport
+type DiffView struct {
+	title        string
+	worktreePath string
+	baseBranch   string
+	stashRef     string // set when showing a stash instead of worktree changes
+	parent       DialogType
+	mode         git.DiffMode
+	files        []git.FileDiff
+	selected     int
+	viewport     viewport.Model
+	width        int
+	height       int
+}
+
+func NewDiffView(worktreeName, worktreePath, baseBranch string, width, height int) (DiffView, error) {
+	d := DiffView{
+		title:        fmt.Sprintf("Diff - %s", worktreeName),
+		worktreePath: worktreePath,
+		baseBranch:   baseBranch,
+		parent:       DialogNone,
+		mode:         git.DiffUnstaged,
+		viewport:     viewport.New(0, 0),
+	}
+	d.SetSize(width, height)
+	if err := d.load(); err != nil {
+		return d, err
+	}
+	return d, nil
+}
+
+// NewStashDiffView creates a diff view for a stash that returns to parent when closed
+func NewStashDiffView(stash git.Stash, repoPath string, parent DialogType, width, height int) (DiffView, error) {
+	d := DiffView{
+		title:        fmt.Sprintf("Stash %s - %s", stash.Ref, stash.Message),
+		worktreePath: repoPath,
+		stashRef:     stash.Ref,
+		parent:       parent,
+		viewport:     viewport.New(0, 0),
+	}
+	d.SetSize(width, height)
+	if err := d.load(); err != nil {
+		return d, err
+	}
+	return d, nil
+}
+
+// load fetches the diff for the current mode and shows the first file
+func (d *DiffView) load() error {
+	var files []git.FileDiff
+	var err error
+	if d.stashRef != "" {
+		files, err = git.StashDiff(d.worktreePath, d.stashRef)
+	} else {
+		files, err = git.GetDiff(d.worktreePath, d.mode, d.baseBranch)
+	}
+	if err != nil {
+		d.files = nil
+		d.selected = 0
+		d.refreshContent()
+		return err
+	}
+	d.files = files
+	d.selected = 0
+	d.refreshContent()
+	return nil
+}
+
+// SetSize adapts the view to the terminal size
+func (d *DiffView) SetSize(width, height int) {
+	d.width = width - 4
+	d.height = height - 2
+
+	// Subtract padding, file list and separator as well as title, tabs and help lines
+	d.viewport.Width = max(10, d.width-4-diffFileListWidth-1)
+	d.viewport.Height = max(3, d.height-5)
+}
+
+// refreshContent shows the patch of the selected file in the viewport
+func (d *DiffView) refreshContent() {
+	if len(d.files) == 0 {
+		d.viewport.SetContent(infoStyle.Render("No changes"))
+		return
+	}
+
+	lines := strings.Split(d.files[d.selected].Patch, "\n")
+	inHunk := false
+	for i, line := range lines {
+		inHunk = inHunk || strings.HasPrefix(line, "@@")
+		lines[i] = colorizeDiffLine(line, inHunk)
+	}
+	d.viewport.SetContent(strings.Join(lines, "\n"))
+	d.viewport.GotoTop()
+}
+
+// colorizeDiffLine styles a single line of a unified diff; before the first hunk all lines are file headers
+func colorizeDiffLine(line string, inHunk bool) string {
+	switch {
+	case !inHunk:
+		return diffMetaStyle.Render(line)
+	case strings.HasPrefix(line, "@@"):
+		return diffHunkStyle.Render(line)
+	case strings.HasPrefix(line, "+"):
+		return diffAddedStyle.Render(line)
+	case strings.HasPrefix(line, "-"):
+		return diffRemovedStyle.Render(line)
+	}
+	return line
+}
+
+func (d *DiffView) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if d.selected > 0 {
+				d.selected--
+				d.refreshContent()
+			}
+			return nil
+		case "down", "j":
+			if d.selected < len(d.files)-1 {
+				d.selected++
+				d.refreshContent()
+			}
+			return nil
+		case "K":
+			d.viewport.LineUp(1)
+			return nil
+		case "J":
+			d.viewport.LineDown(1)
+			return nil
+		case "tab":
+			return d.setMode((d.mode + 1) % 3)
+		case "1":
+			return d.setMode(git.DiffUnstaged)
+		case "2":
+			return d.setMode(git.DiffStaged)
+		case "3":
+			return d.setMode(git.DiffBranch)
+		}
+	}
+
+	var cmd tea.Cmd
+	d.viewport, cmd = d.viewport.Update(msg)
+	return cmd
+}
+
+// setMode switches between unstaged, staged and branch changes
+func (d *DiffView) setMode(mode git.DiffMode) tea.Cmd {
+	if mode == d.mode || d.stashRef != "" {
+		return nil
+	}
+	d.mode = mode
+	if err := d.load(); err != nil {
+		return showError(fmt.Sprintf("Failed to load diff: %v", err))
+	}
+	return nil
+}
+
+func (d *DiffView) View() string {
+	var b strings.Builder
+
+	b.WriteString(headerStyle.Render(d.title))
+	b.WriteString("\n")
+
+	// Mode tabs
+	var tabs []string
+	if d.stashRef != "" {
+		tabs = append(tabs, infoStyle.Render("Stashed changes"))
+	} else {
+		for i, mode := range []git.DiffMode{git.DiffUnstaged, git.DiffStaged, git.DiffBranch} {
+			label := fmt.Sprintf("%d %s", i+1, mode)
+			if mode == git.DiffBranch && d.baseBranch != "" {
+				label += " vs " + d.baseBranch
+			}
+			if mode == d.mode {
+				tabs = append(tabs, selectedItemStyle.Render(label))
+			} else {
+				tabs = append(tabs, itemStyle.Render(label))
+			}
+		}
+	}
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, tabs...))
+	b.WriteString("\n\n")
+
+	// File list
+	var files []string
+	if len(d.files) == 0 {
+		files = append(files, infoStyle.Render("No files"))
+	}
+	for i, file := range d.files {
+		stats := fmt.Sprintf("+%d -%d", file.Added, file.Deleted)
+		path := truncateLeft(file.Path, diffFileListWidth-len(stats)-5)
+		text := fmt.Sprintf("%s %s", path, stats)
+		if i == d.selected {
+			files = append(files, selectedItemStyle.Render("> "+text))
+		} else {
+			files = append(files, itemStyle.Render("  "+text))
+		}
+	}
+	fileList := lipgloss.NewStyle().
+		Width(diffFileListWidth).
+		Height(d.viewport.Height).
+		MaxHeight(d.viewport.Height).
+		Render(strings.Join(files, "\n"))
+
+	separator := lipgloss.NewStyle().
+		Foreground(borderColor).
+		Render(strings.Repeat("│\n", d.viewport.Height-1) + "│")
+
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, fileList, separator, d.viewport.View()))
+	b.WriteString("\n")
+
+	bindings := []key.Binding{diffFileHint, diffScrollHint}
+	if d.stashRef == "" {
+		bindings = append(bindings, diffModeHint)
+	}
+	bindings = append(bindings, closeHint, hint(fmt.Sprintf("%3.f%%", d.viewport.ScrollPercent()*100), ""))
+	b.WriteString(helpLine(bindings...))
+
+	dialogStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(0, 2).
+		Width(d.width).
+		Height(d.height)
+
+	return dialogStyle.Render(b.String())
+}
+
+// truncateLeft shortens s to at most width characters by cutting from the left
+func truncateLeft(s string, width int) string {
+	runes := []rune(s)
+	if width <= 1 || len(runes) <= width {
+		return s
+	}
+	return "…" + string(runes[len(runes)-width+1:])
+}
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/michael-rose/workman/internal/config"
+	"github.com/michael-rose/workman/internal/state"
+	"github.com/michael-rose/workman/internal/template"
+)
+
+// Editor modes of the config
+const (
+	editorModeAuto     = "auto"     // terminal editors are recognized by name
+	editorModeGUI      = "gui"      // the editor opens its own window and workman keeps running
+	editorModeTerminal = "terminal" // the editor takes over the terminal until it exits
+)
+
+// terminalEditors are editors that run in the terminal, recognized in the auto mode
+var terminalEditors = map[string]bool{
+	"vi": true, "vim": true, "nvim": true, "nano": true, "pico": true, "micro": true,
+	"hx": true, "helix": true, "kak": true, "joe": true, "ne": true, "mg": true,
+	"emacs": true, "emacsclient": true, "jed": true, "mcedit": true,
+}
+
+// editorClosedMsg is sent when a terminal editor opened on a worktree exits
+type editorClosedMsg struct {
+	err error
+}
+
+// editorCommand builds the command opening a worktree in the editor
+// The editor_command template is split into arguments at whitespace outside of variables,
+// so that paths with spaces stay one argument; the worktree path is appended if the
+// template does not contain it. Without editor_command, $VISUAL or $EDITOR is used
+func editorCommand(cfg *config.Config, repo *config.Repository, wt state.Worktree) ([]string, error) {
+	text := strings.TrimSpace(cfg.EditorCommand)
+	if text == "" {
+		text = strings.TrimSpace(os.Getenv("VISUAL"))
+	}
+	if text == "" {
+		text = strings.TrimSpace(os.Getenv("EDITOR"))
+	}
+	if text == "" {
+		return nil, fmt.Errorf("no editor configured. Set 'editor_command' in config.toml or $EDITOR")
+	}
+
+	t, err := template.Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	args := t.ExecuteFields(templateVars(t, cfg, repo, wt))
+	if !t.Uses("worktree_path") {
+		args = append(args, wt.Path)
+	}
+	return args, nil
+}
+
+// isTerminalEditor reports whether an editor command runs in the terminal
+func isTerminalEditor(mode string, args []string) bool {
+	switch mode {
+	case editorModeTerminal:
+		return true
+	case editorModeGUI:
+		return false
+	}
+
+	name := filepath.Base(args[0])
+	if name == "emacs" || name == "emacsclient" {
+		// Emacs opens a window unless told to stay in the terminal
+		for _, arg := range args[1:] {
+			if arg == "-nw" || arg == "-t" || arg == "--no-window-system" || arg == "--tty" {
+				return true
+			}
+		}
+		return false
+	}
+	return terminalEditors[name]
+}
+
+// openInEditor opens the selected worktree in the configured editor
+// Terminal editors suspend the TUI until they exit, GUI editors are started detached in the background
+func (m Model) openInEditor() (tea.Model, tea.Cmd) {
+	repo := m.state.GetSelectedRepo()
+	wt := m.state.Worktrees[m.state.SelectedWTIndex]
+
+	args, err := editorCommand(m.state.Config, repo, wt)
+	if err != nil {
+		return m, showError(err.Error())
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = wt.Path
+	m.errorMsg = ""
+	m.successMsg = ""
+
+	if isTerminalEditor(m.state.Config.EditorMode, args) {
+		return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+			return editorClosedMsg{err: err}
+		})
+	}
+
+	detach(cmd)
+	if err := cmd.Start(); err != nil {
+		return m, showError(fmt.Sprintf("Failed to start editor: %v", err))
+	}
+	// Reap the editor process when it exits; GUI launchers often return immediately
+	go func() {
+		_ = cmd.Wait()
+	}()
+	m.successMsg = fmt.Sprintf("Opened %s in %s", wt.Name, filepath.Base(args[0]))
+	return m, nil
+}
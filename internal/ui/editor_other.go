@@ -0,0 +1,8 @@
+//go:build !unix
+
+package ui
+
+import "os/exec"
+
+// detach leaves the command as it is where sessions are not available
+func detach(cmd *exec.Cmd) {}
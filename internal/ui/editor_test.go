@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/michael-rose/workman/internal/config"
+	"github.com/michael-rose/workman/internal/state"
+)
+
+func TestEditorCommand(t *testing.T) {
+	repo := &config.Repository{Name: "repo"}
+	wt := state.Worktree{Name: "feature", Branch: "feature/x", Path: "/work/my repo/feature"}
+
+	tests := []struct {
+		name     string
+		command  string
+		editor   string
+		expected []string
+	}{
+		{name: "path appended", command: "code", expected: []string{"code", "/work/my repo/feature"}},
+		{name: "path placeholder keeps spaces", command: "code -n ${worktree_path}", expected: []string{"code", "-n", "/work/my repo/feature"}},
+		{name: "other variables", command: "idea --title ${repo_name}:${branch_name} ${path}", expected: []string{"idea", "--title", "repo:feature/x", "/work/my repo/feature"}},
+		{name: "EDITOR fallback", editor: "nvim", expected: []string{"nvim", "/work/my repo/feature"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("VISUAL", "")
+			t.Setenv("EDITOR", tt.editor)
+			args, err := editorCommand(&config.Config{EditorCommand: tt.command}, repo, wt)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(args, tt.expected) {
+				t.Errorf("got %q, want %q", args, tt.expected)
+			}
+		})
+	}
+
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+	if _, err := editorCommand(&config.Config{}, repo, wt); err == nil {
+		t.Error("expected an error without any editor")
+	}
+}
+
+func TestIsTerminalEditor(t *testing.T) {
+	tests := []struct {
+		mode     string
+		args     []string
+		expected bool
+	}{
+		{editorModeAuto, []string{"code", "/p"}, false},
+		{editorModeAuto, []string{"/usr/bin/nvim", "/p"}, true},
+		{editorModeAuto, []string{"emacs", "/p"}, false},
+		{editorModeAuto, []string{"emacs", "-nw", "/p"}, true},
+		{"", []string{"vim", "/p"}, true},
+		{editorModeTerminal, []string{"code", "/p"}, true},
+		{editorModeGUI, []string{"vim", "/p"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := isTerminalEditor(tt.mode, tt.args); got != tt.expected {
+			t.Errorf("isTerminalEditor(%q, %q) = %v, want %v", tt.mode, tt.args, got, tt.expected)
+		}
+	}
+}
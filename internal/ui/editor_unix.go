@@ -0,0 +1,14 @@
+//go:build unix
+
+package ui
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detach starts the command in its own session, so that it keeps running without
+// the terminal and does not get its hangup signal
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
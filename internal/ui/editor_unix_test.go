@@ -0,0 +1,30 @@
+//go:build unix
+
+package ui
+
+import (
+	"os/exec"
+	"syscall"
+	"testing"
+)
+
+func TestDetachStartsNewSession(t *testing.T) {
+	cmd := exec.Command("sleep", "1")
+	detach(cmd)
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	// A new session comes with a new process group led by the editor
+	pgid, err := syscall.Getpgid(cmd.Process.Pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pgid != cmd.Process.Pid {
+		t.Errorf("Expected the editor to lead its own process group, got group %d for pid %d", pgid, cmd.Process.Pid)
+	}
+}
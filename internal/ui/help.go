@@ -0,0 +1,289 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/michael-rose/workman/internal/state"
+)
+
+// helpModel renders the help lines of the main view and all dialogs; it is styled by ApplyTheme
+var helpModel help.Model
+
+func newHelpModel() help.Model {
+	h := help.New()
+	h.Styles.ShortKey = lipgloss.NewStyle().Foreground(primaryColor)
+	h.Styles.ShortDesc = lipgloss.NewStyle().Foreground(mutedColor)
+	h.Styles.ShortSeparator = lipgloss.NewStyle().Foreground(borderColor)
+	h.Styles.FullKey = h.Styles.ShortKey
+	h.Styles.FullDesc = h.Styles.ShortDesc
+	h.Styles.FullSeparator = h.Styles.ShortSeparator
+	h.Styles.Ellipsis = h.Styles.ShortSeparator
+	return h
+}
+
+// helpLine renders bindings as a single help line
+func helpLine(bindings ...key.Binding) string {
+	return helpStyle.Render(helpModel.ShortHelpView(bindings))
+}
+
+// hint creates a binding that is only shown in the help; the keys of dialogs and views are fixed
+func hint(keys, desc string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys), key.WithHelp(keys, desc))
+}
+
+// withDesc returns a copy of a binding with a description for a specific context
+func withDesc(b key.Binding, desc string) key.Binding {
+	b.SetHelp(b.Help().Key, desc)
+	return b
+}
+
+// Keys of dialogs and views
+var (
+	closeHint       = hint("esc/q", "close")
+	cancelHint      = hint("esc", "cancel")
+	confirmHint     = hint("y", "confirm")
+	rejectHint      = hint("n/esc", "cancel")
+	anyCancelHint   = hint("any other key", "cancel")
+	saveHint        = hint("ctrl+s", "save")
+	createHint      = hint("ctrl+s", "create")
+	nextFieldHint   = hint("enter", "next field")
+	suggestionHint  = hint("↑↓", "suggestion")
+	selectHint      = hint("tab/enter", "select")
+	navigateHint    = hint("↑↓/jk", "navigate")
+	scrollHint      = hint("↑↓/jk pgup/pgdn", "scroll")
+	outputCloseHint = hint("esc/q/enter", "close")
+
+	diffFileHint   = hint("↑↓/jk", "file")
+	diffScrollHint = hint("J/K pgup/pgdn", "scroll")
+	diffModeHint   = hint("tab/1-3", "mode")
+
+	stashHint       = hint("s", "stash")
+	stashApplyHint  = hint("a", "apply")
+	stashPopHint    = hint("p", "pop")
+	stashDropHint   = hint("x", "drop")
+	stashDiffHint   = hint("enter/d", "show diff")
+	stashSubmitHint = hint("enter", "stash changes (incl. untracked)")
+
+	syncContinueHint = hint("c", "continue")
+	syncAbortHint    = hint("a", "abort")
+	syncCloseHint    = hint("esc", "close (leave in progress)")
+
+	pushConfirmHint = hint("y", "push")
+
+	cleanupSelectHint    = hint("space", "select")
+	cleanupSelectAllHint = hint("a", "select all")
+	cleanupRemoveHint    = hint("enter/x", "remove selected")
+
+	overviewSortHint   = hint("s", "sort")
+	overviewFilterHint = hint("/", "filter")
+	overviewReloadHint = hint("r", "reload")
+	overviewJumpHint   = hint("enter", "go to worktree")
+	filterApplyHint    = hint("enter", "apply filter")
+	filterClearHint    = hint("esc", "clear filter")
+
+	helpCloseHint = hint("esc/q/?", "close")
+
+	paletteSelectHint = hint("↑↓", "select")
+	paletteRunHint    = hint("enter", "run")
+
+	actionRunHint    = hint("enter", "run")
+	actionNumberHint = hint("1-9", "run by number")
+
+	runsLogHint = hint("enter", "show log")
+
+	scriptCancelHint = hint("esc/ctrl+c", "cancel script")
+)
+
+// keyGroup is a titled group of bindings in the help overlay
+type keyGroup struct {
+	title    string
+	bindings []key.Binding
+}
+
+// keyGroups lists all bindings grouped by the context they apply to
+func (m Model) keyGroups() []keyGroup {
+	k := m.keys
+	groups := []keyGroup{
+		{"Navigation", []key.Binding{k.Up, k.Down, k.PageUp, k.PageDown, k.Top, k.Bottom, k.SwitchPane, k.ReposPane, k.WorktreesPane}},
+		{"Repositories", []key.Binding{
+			withDesc(k.Add, "add repository"), withDesc(k.Delete, "delete repository"), k.Script,
+			k.Refresh, k.RefreshAll,
+		}},
+		{"Worktrees", []key.Binding{
+			withDesc(k.Add, "add worktree"), withDesc(k.Delete, "delete worktree"), withDesc(k.Open, "run enter script"),
+			k.Editor, k.Notes, k.Details, k.Diff, k.Stash, k.Sync, k.SyncAll, k.Push, k.PushAll, k.Fetch, k.Lock, k.Yank, k.Cleanup,
+		}},
+		{"Marked worktrees", []key.Binding{
+			k.Mark, k.MarkAll, k.ClearMarks, withDesc(k.Open, "run enter script on marked"),
+			withDesc(k.Delete, "delete marked"), withDesc(k.Push, "push marked"), withDesc(k.Fetch, "fetch marked"),
+			withDesc(k.Lock, "lock marked"), withDesc(k.Yank, "yank marked"),
+		}},
+		{"Actions", m.actionBindings()},
+		{"Layout", []key.Binding{k.ShrinkRepos, k.GrowRepos, k.ToggleRepos}},
+		{"General", []key.Binding{k.AllWorktrees, k.Runs, k.FetchAll, k.Palette, k.Help, k.Quit}},
+		{"Diff view", []key.Binding{diffFileHint, diffScrollHint, diffModeHint, closeHint}},
+		{"Stash view", []key.Binding{stashHint, stashApplyHint, stashPopHint, stashDropHint, stashDiffHint, closeHint}},
+		{"All worktrees view", []key.Binding{navigateHint, overviewSortHint, overviewFilterHint, overviewReloadHint, overviewJumpHint, closeHint}},
+		{"Script runs view", []key.Binding{navigateHint, runsLogHint, overviewReloadHint, closeHint}},
+		{"Cleanup view", []key.Binding{cleanupSelectHint, cleanupSelectAllHint, cleanupRemoveHint, closeHint}},
+		{"Post-create script", []key.Binding{scrollHint, scriptCancelHint}},
+		{"Sync conflicts", []key.Binding{syncContinueHint, syncAbortHint, syncCloseHint}},
+		{"Action picker", []key.Binding{navigateHint, actionRunHint, actionNumberHint, cancelHint}},
+		{"Command palette", []key.Binding{paletteSelectHint, paletteRunHint, cancelHint}},
+		{"Dialogs", []key.Binding{nextFieldHint, saveHint, confirmHint, rejectHint, scrollHint}},
+	}
+
+	// Without named actions with keys their group is left out
+	shown := groups[:0]
+	for _, g := range groups {
+		if len(g.bindings) > 0 {
+			shown = append(shown, g)
+		}
+	}
+	return shown
+}
+
+// contextKeys returns the bindings relevant to the active pane for the help bar
+func (m Model) contextKeys() []key.Binding {
+	k := m.keys
+	if m.state.ActivePane == state.ReposPane {
+		return []key.Binding{
+			k.Help, k.SwitchPane, withDesc(k.Add, "add repository"), withDesc(k.Delete, "delete repository"),
+			k.Script, k.AllWorktrees, k.Refresh, k.RefreshAll, k.Quit,
+		}
+	}
+	if len(m.state.MarkedWorktrees()) > 0 {
+		return []key.Binding{
+			k.Help, k.Mark, k.MarkAll, k.ClearMarks, withDesc(k.Open, "run script"), withDesc(k.Delete, "delete"),
+			k.Push, k.Fetch, k.Lock, k.Yank, k.Quit,
+		}
+	}
+	return []key.Binding{
+		k.Help, k.SwitchPane, withDesc(k.Add, "add worktree"), withDesc(k.Delete, "delete worktree"), k.Open,
+		k.Editor, k.Notes, k.Diff, k.Sync, k.Push, k.Mark, k.Yank, k.Details, k.Stash, k.Fetch, k.Lock, k.Cleanup, k.Quit,
+	}
+}
+
+func (m Model) renderHelp() string {
+	h := helpModel
+	h.Width = m.width - 2
+
+	// The help model stops truncating when the ellipsis does not fit, so drop bindings until the line does
+	bindings := m.contextKeys()
+	line := h.ShortHelpView(bindings)
+	for len(bindings) > 1 && lipgloss.Width(line) > h.Width {
+		bindings = bindings[:len(bindings)-1]
+		line = h.ShortHelpView(bindings)
+	}
+	return helpStyle.Render(line)
+}
+
+// HelpView lists all key bindings grouped by context in a scrollable overlay
+type HelpView struct {
+	groups   []keyGroup
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+func NewHelpView(groups []keyGroup, width, height int) HelpView {
+	v := HelpView{
+		groups:   groups,
+		viewport: viewport.New(0, 0),
+	}
+	v.SetSize(width, height)
+	return v
+}
+
+// SetSize adapts the view to the terminal size and lays out the groups in as many columns as fit
+func (v *HelpView) SetSize(width, height int) {
+	v.width = min(120, width-4)
+	v.height = height - 4
+
+	rendered := make([]string, len(v.groups))
+	columnWidth := 0
+	for i, group := range v.groups {
+		rendered[i] = helpTitleStyle.Render(group.title) + "\n" +
+			helpModel.FullHelpView([][]key.Binding{group.bindings})
+		columnWidth = max(columnWidth, lipgloss.Width(rendered[i]))
+	}
+	columnWidth += 4
+
+	// Fill the columns one after another, each about as high as the total divided by the columns
+	columns := max(1, min(3, (v.width-4)/columnWidth))
+	total := 0
+	for _, r := range rendered {
+		total += lipgloss.Height(r) + 1
+	}
+	target := (total + columns - 1) / columns
+
+	var cols []string
+	var current []string
+	currentHeight := 0
+	for _, r := range rendered {
+		h := lipgloss.Height(r) + 1
+		if currentHeight > 0 && currentHeight+h > target && len(cols) < columns-1 {
+			cols = append(cols, lipgloss.NewStyle().Width(columnWidth).Render(strings.Join(current, "\n\n")))
+			current, currentHeight = nil, 0
+		}
+		current = append(current, r)
+		currentHeight += h
+	}
+	cols = append(cols, lipgloss.NewStyle().Width(columnWidth).Render(strings.Join(current, "\n\n")))
+	v.viewport.SetContent(lipgloss.JoinHorizontal(lipgloss.Top, cols...))
+
+	// Subtract padding as well as title and help lines
+	v.viewport.Width = max(10, v.width-4)
+	v.viewport.Height = max(3, min(v.viewport.TotalLineCount(), v.height-8))
+}
+
+func (v *HelpView) Update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	v.viewport, cmd = v.viewport.Update(msg)
+	return cmd
+}
+
+func (v *HelpView) View() string {
+	var b strings.Builder
+
+	b.WriteString(headerStyle.Render("Key bindings"))
+	b.WriteString("\n\n")
+	b.WriteString(v.viewport.View())
+	b.WriteString("\n")
+	b.WriteString(infoStyle.Render("Main view keys can be changed in the [keys] table of config.toml"))
+	b.WriteString("\n")
+	b.WriteString(helpLine(scrollHint, helpCloseHint))
+
+	dialogStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(1, 2).
+		Width(v.width)
+
+	return dialogStyle.Render(b.String())
+}
+
+// openHelp opens the help overlay
+func (m Model) openHelp() (tea.Model, tea.Cmd) {
+	m.helpView = NewHelpView(m.keyGroups(), m.width, m.height)
+	m.dialogType = DialogHelp
+	return m, nil
+}
+
+func (m Model) handleHelpKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q", "?":
+		m.dialogType = DialogNone
+		return m, nil
+	}
+
+	cmd := m.helpView.Update(msg)
+	return m, cmd
+}
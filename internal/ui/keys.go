@@ -0,0 +1,256 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// KeyMap holds the key bindings of the main view
+type KeyMap struct {
+	Up            key.Binding
+	Down          key.Binding
+	PageUp        key.Binding
+	PageDown      key.Binding
+	Top           key.Binding
+	Bottom        key.Binding
+	SwitchPane    key.Binding
+	ReposPane     key.Binding
+	WorktreesPane key.Binding
+	ShrinkRepos   key.Binding
+	GrowRepos     key.Binding
+	ToggleRepos   key.Binding
+	Add           key.Binding
+	Delete        key.Binding
+	Open          key.Binding
+	Editor        key.Binding
+	Notes         key.Binding
+	Script        key.Binding
+	Details       key.Binding
+	Diff          key.Binding
+	Stash         key.Binding
+	Sync          key.Binding
+	SyncAll       key.Binding
+	Push          key.Binding
+	PushAll       key.Binding
+	Fetch         key.Binding
+	FetchAll      key.Binding
+	Mark          key.Binding
+	MarkAll       key.Binding
+	ClearMarks    key.Binding
+	Lock          key.Binding
+	Yank          key.Binding
+	Cleanup       key.Binding
+	AllWorktrees  key.Binding
+	Runs          key.Binding
+	Refresh       key.Binding
+	RefreshAll    key.Binding
+	Palette       key.Binding
+	Help          key.Binding
+	Quit          key.Binding
+}
+
+// keyAction is a binding together with the name used for it in the [keys] table
+type keyAction struct {
+	name    string
+	binding *key.Binding
+}
+
+// actions lists the bindings in the order they are shown in the help
+func (k *KeyMap) actions() []keyAction {
+	return []keyAction{
+		{"up", &k.Up},
+		{"down", &k.Down},
+		{"page_up", &k.PageUp},
+		{"page_down", &k.PageDown},
+		{"top", &k.Top},
+		{"bottom", &k.Bottom},
+		{"switch_pane", &k.SwitchPane},
+		{"repos_pane", &k.ReposPane},
+		{"worktrees_pane", &k.WorktreesPane},
+		{"shrink_repos", &k.ShrinkRepos},
+		{"grow_repos", &k.GrowRepos},
+		{"toggle_repos", &k.ToggleRepos},
+		{"add", &k.Add},
+		{"delete", &k.Delete},
+		{"open", &k.Open},
+		{"editor", &k.Editor},
+		{"notes", &k.Notes},
+		{"script", &k.Script},
+		{"details", &k.Details},
+		{"diff", &k.Diff},
+		{"stash", &k.Stash},
+		{"sync", &k.Sync},
+		{"sync_all", &k.SyncAll},
+		{"push", &k.Push},
+		{"push_all", &k.PushAll},
+		{"fetch", &k.Fetch},
+		{"fetch_all", &k.FetchAll},
+		{"mark", &k.Mark},
+		{"mark_all", &k.MarkAll},
+		{"clear_marks", &k.ClearMarks},
+		{"lock", &k.Lock},
+		{"yank", &k.Yank},
+		{"cleanup", &k.Cleanup},
+		{"all_worktrees", &k.AllWorktrees},
+		{"runs", &k.Runs},
+		{"refresh", &k.Refresh},
+		{"refresh_all", &k.RefreshAll},
+		{"palette", &k.Palette},
+		{"help", &k.Help},
+		{"quit", &k.Quit},
+	}
+}
+
+// lookup returns the binding of an action by its name in the [keys] table
+func (k *KeyMap) lookup(name string) (key.Binding, bool) {
+	for _, action := range k.actions() {
+		if action.name == name {
+			return *action.binding, true
+		}
+	}
+	return key.Binding{}, false
+}
+
+// binding creates a binding whose help shows its keys
+func binding(desc string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(keysHelp(keys), desc))
+}
+
+// DefaultKeyMap returns the built-in key bindings
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up:            binding("up", "up", "k"),
+		Down:          binding("down", "down", "j"),
+		PageUp:        binding("page up", "pgup"),
+		PageDown:      binding("page down", "pgdown"),
+		Top:           binding("top", "g", "home"),
+		Bottom:        binding("bottom", "G", "end"),
+		SwitchPane:    binding("switch pane", "tab"),
+		ReposPane:     binding("repositories", "h"),
+		WorktreesPane: binding("worktrees", "l"),
+		ShrinkRepos:   binding("shrink repositories", "<"),
+		GrowRepos:     binding("grow repositories", ">"),
+		ToggleRepos:   binding("hide/show repositories", "b"),
+		Add:           binding("add", "+"),
+		Delete:        binding("delete", "-"),
+		Open:          binding("open", "enter"),
+		Editor:        binding("open in editor", "e"),
+		Notes:         binding("notes", "n"),
+		Script:        binding("post-create script", "s"),
+		Details:       binding("details", "i"),
+		Diff:          binding("diff", "d"),
+		Stash:         binding("stash", "z"),
+		Sync:          binding("sync", "u"),
+		SyncAll:       binding("sync all", "U"),
+		Push:          binding("push", "p"),
+		PushAll:       binding("push all", "P"),
+		Fetch:         binding("fetch", "f"),
+		FetchAll:      binding("fetch all"), // only in the command palette unless bound in the [keys] table
+		Mark:          binding("mark", " "),
+		MarkAll:       binding("mark all", "V", "*"),
+		ClearMarks:    binding("clear marks", "esc"),
+		Lock:          binding("lock", "L"),
+		Yank:          binding("yank", "y"),
+		Cleanup:       binding("cleanup", "C"),
+		AllWorktrees:  binding("all worktrees", "w"),
+		Runs:          binding("script runs", "o"),
+		Refresh:       binding("refresh", "r"),
+		RefreshAll:    binding("refresh all", "R"),
+		Palette:       binding("commands", ":", "ctrl+p"),
+		Help:          binding("help", "?"),
+		Quit:          binding("quit", "q", "ctrl+c"),
+	}
+}
+
+// NewKeyMap returns the default key bindings with the overrides of the [keys] table applied
+// Overrides map action names to keys; an empty list disables the action
+// Unknown actions and keys bound to more than one action are reported as errors
+func NewKeyMap(overrides map[string][]string) (KeyMap, error) {
+	keys := DefaultKeyMap()
+	actions := keys.actions()
+
+	byName := map[string]*key.Binding{}
+	for _, action := range actions {
+		byName[action.name] = action.binding
+	}
+
+	// Sorted for deterministic error messages
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		b, ok := byName[name]
+		if !ok {
+			return keys, fmt.Errorf("unknown action '%s'", name)
+		}
+		var bound []string
+		for _, k := range overrides[name] {
+			k = normalizeKey(k)
+			if k == "" {
+				return keys, fmt.Errorf("empty key for action '%s'", name)
+			}
+			bound = append(bound, k)
+		}
+		if len(bound) == 0 {
+			if b == &keys.Quit {
+				return keys, fmt.Errorf("action 'quit' needs at least one key")
+			}
+			b.SetEnabled(false)
+			continue
+		}
+		*b = binding(b.Help().Desc, bound...)
+	}
+
+	owners := map[string]string{}
+	for _, action := range actions {
+		if !action.binding.Enabled() {
+			continue
+		}
+		for _, k := range action.binding.Keys() {
+			if owner, ok := owners[k]; ok {
+				return keys, fmt.Errorf("key '%s' is bound to both '%s' and '%s'", displayKey(k), owner, action.name)
+			}
+			owners[k] = action.name
+		}
+	}
+
+	return keys, nil
+}
+
+// normalizeKey converts the names used in the config to the key strings of Bubble Tea
+func normalizeKey(k string) string {
+	if strings.TrimSpace(k) == "" && k != " " {
+		return ""
+	}
+	if k == "space" {
+		return " "
+	}
+	return k
+}
+
+// displayKey converts a key string of Bubble Tea to the way it is shown in the help
+func displayKey(k string) string {
+	switch k {
+	case " ":
+		return "space"
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	}
+	return k
+}
+
+func keysHelp(keys []string) string {
+	shown := make([]string, len(keys))
+	for i, k := range keys {
+		shown[i] = displayKey(k)
+	}
+	return strings.Join(shown, "/")
+}
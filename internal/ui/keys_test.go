@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNewKeyMap(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string][]string
+		wantErr   string
+	}{
+		{name: "defaults"},
+		{name: "rebind", overrides: map[string][]string{"add": {"a"}, "delete": {"x", "-"}}},
+		{name: "swap", overrides: map[string][]string{"notes": {"s"}, "script": {"n"}}},
+		{name: "disable", overrides: map[string][]string{"yank": {}}},
+		{name: "disabled action frees its key", overrides: map[string][]string{"yank": {}, "notes": {"y"}}},
+		{name: "conflict with default", overrides: map[string][]string{"add": {"n"}}, wantErr: "key 'n' is bound to both 'add' and 'notes'"},
+		{name: "conflict between overrides", overrides: map[string][]string{"add": {"a"}, "lock": {"a"}}, wantErr: "key 'a' is bound to both 'add' and 'lock'"},
+		{name: "space", overrides: map[string][]string{"mark": {"x"}, "add": {"space"}}},
+		{name: "unknown action", overrides: map[string][]string{"frobnicate": {"x"}}, wantErr: "unknown action 'frobnicate'"},
+		{name: "empty key", overrides: map[string][]string{"add": {""}}, wantErr: "empty key for action 'add'"},
+		{name: "quit without keys", overrides: map[string][]string{"quit": {}}, wantErr: "action 'quit' needs at least one key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewKeyMap(tt.overrides)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewKeyMap_Overrides(t *testing.T) {
+	keys, err := NewKeyMap(map[string][]string{"add": {"a", "space"}, "mark": {"m"}, "yank": {}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	plus := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'+'}}
+	a := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}}
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	y := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}}
+
+	if key.Matches(plus, keys.Add) {
+		t.Error("default key of an overridden action still matches")
+	}
+	if !key.Matches(a, keys.Add) || !key.Matches(space, keys.Add) {
+		t.Error("overridden keys do not match")
+	}
+	if key.Matches(space, keys.Mark) {
+		t.Error("space still matches mark")
+	}
+	if key.Matches(y, keys.Yank) {
+		t.Error("disabled action matches")
+	}
+	if got := keys.Add.Help().Key; got != "a/space" {
+		t.Errorf("help key = %q, want %q", got, "a/space")
+	}
+	if keys.Yank.Enabled() {
+		t.Error("disabled action is enabled")
+	}
+}
@@ -0,0 +1,207 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/michael-rose/workman/internal/config"
+	"github.com/michael-rose/workman/internal/state"
+)
+
+// Layout settings of the config
+const (
+	layoutAuto       = "auto"       // side by side, stacked on narrow terminals
+	layoutHorizontal = "horizontal" // always side by side
+	layoutVertical   = "vertical"   // always stacked
+)
+
+const (
+	minWidth         = 20 // smallest terminal the panes are rendered in
+	minHeight        = 10
+	minStackedHeight = 14 // stacked panes need room for two borders and headers
+	stackWidth       = 70 // terminals narrower than this stack the panes in the auto layout
+	minSplit         = 15 // bounds of the percentage taken by the repositories pane
+	maxSplit         = 75
+	splitStep        = 5 // percentage the resize keys change the split by
+)
+
+// paneLayout is the position of the panes on the screen, shared by rendering and mouse handling
+// Widths include the padding but not the border; heights exclude the border
+type paneLayout struct {
+	top             int  // first line of the panes, below the feedback message
+	stacked         bool // repositories above worktrees instead of side by side
+	hideRepos       bool // only the worktrees pane is shown
+	reposWidth      int
+	reposHeight     int
+	worktreesWidth  int
+	worktreesHeight int
+}
+
+// clampSplit keeps the repositories pane percentage within bounds
+func clampSplit(split int) int {
+	return max(minSplit, min(maxSplit, split))
+}
+
+// stacked reports whether the panes are arranged vertically for a terminal width
+func stacked(cfg *config.Config, width int) bool {
+	switch cfg.Layout {
+	case layoutVertical:
+		return true
+	case layoutHorizontal:
+		return false
+	default:
+		return width < stackWidth
+	}
+}
+
+// layout computes the pane dimensions from the terminal size and the layout settings
+func (m Model) layout() paneLayout {
+	cfg := m.state.Config
+	split := clampSplit(cfg.Split)
+	l := paneLayout{
+		stacked:   stacked(cfg, m.width),
+		hideRepos: cfg.HideRepos,
+	}
+	if feedback := m.renderFeedback(); feedback != "" {
+		l.top = lipgloss.Height(feedback)
+	}
+
+	switch {
+	case l.hideRepos:
+		l.worktreesWidth = m.width - 4
+		l.worktreesHeight = m.height - 6
+	case l.stacked:
+		// Both panes span the width and share the height, each with its own border
+		available := m.height - 8
+		l.reposWidth = m.width - 4
+		l.worktreesWidth = m.width - 4
+		l.reposHeight = max(minListHeight, available*split/100)
+		l.worktreesHeight = max(minListHeight, available-l.reposHeight)
+	default:
+		l.reposWidth = max(1, m.width*split/100-4)
+		l.worktreesWidth = max(1, m.width-m.width*split/100-4)
+		l.reposHeight = m.height - 6
+		l.worktreesHeight = m.height - 6
+	}
+	return l
+}
+
+// fits reports whether the terminal is large enough for the layout
+func (m Model) fits() bool {
+	if m.width < minWidth || m.height < minHeight {
+		return false
+	}
+	return !stacked(m.state.Config, m.width) || m.state.Config.HideRepos || m.height >= minStackedHeight
+}
+
+// paneAt returns the pane at a screen position and the list row in it
+// Rows count from the first list item; the header and borders are negative or beyond the list
+func (l paneLayout) paneAt(x, y int) (state.Pane, int, bool) {
+	y -= l.top
+	if x < 0 || y < 0 {
+		return "", 0, false
+	}
+
+	// One line of border and one header line precede the list
+	switch {
+	case l.hideRepos:
+		if x < l.worktreesWidth+2 && y < l.worktreesHeight+2 {
+			return state.WorktreesPane, y - 2, true
+		}
+	case l.stacked:
+		if x >= l.reposWidth+2 {
+			return "", 0, false
+		}
+		if y < l.reposHeight+2 {
+			return state.ReposPane, y - 2, true
+		}
+		y -= l.reposHeight + 2
+		if y < l.worktreesHeight+2 {
+			return state.WorktreesPane, y - 2, true
+		}
+	default:
+		reposEnd := l.reposWidth + 2
+		if y >= l.reposHeight+2 || x >= reposEnd+l.worktreesWidth+2 {
+			return "", 0, false
+		}
+		if x < reposEnd {
+			return state.ReposPane, y - 2, true
+		}
+		return state.WorktreesPane, y - 2, true
+	}
+	return "", 0, false
+}
+
+// onDivider reports whether a screen position is on the borders between the two panes
+func (l paneLayout) onDivider(x, y int) bool {
+	if l.hideRepos {
+		return false
+	}
+	y -= l.top
+	if l.stacked {
+		return x >= 0 && x < l.reposWidth+2 && (y == l.reposHeight+1 || y == l.reposHeight+2)
+	}
+	return y >= 0 && y < l.reposHeight+2 && (x == l.reposWidth+1 || x == l.reposWidth+2)
+}
+
+// splitAt returns the repositories pane percentage that puts the divider at a screen position
+func (m Model) splitAt(x, y int) int {
+	l := m.layout()
+	if l.stacked {
+		available := max(1, m.height-8)
+		return clampSplit((y - l.top - 1) * 100 / available)
+	}
+	return clampSplit((x + 3) * 100 / max(1, m.width))
+}
+
+// resizeRepos changes the percentage taken by the repositories pane and saves it
+func (m Model) resizeRepos(split int) (Model, tea.Cmd) {
+	split = clampSplit(split)
+	if split == m.state.Config.Split && !m.state.Config.HideRepos {
+		return m, nil
+	}
+	m.state.Config.Split = split
+	m.state.Config.HideRepos = false
+	return m, saveLayout(m.state.Config)
+}
+
+// toggleRepos hides or shows the repositories pane and saves the choice
+func (m Model) toggleRepos() (Model, tea.Cmd) {
+	m.state.Config.HideRepos = !m.state.Config.HideRepos
+	if m.state.Config.HideRepos {
+		m.state.ActivePane = state.WorktreesPane
+	}
+	return m, saveLayout(m.state.Config)
+}
+
+// saveLayout persists the layout settings
+func saveLayout(cfg *config.Config) tea.Cmd {
+	if err := config.Save(cfg); err != nil {
+		return showError(fmt.Sprintf("Failed to save layout: %v", err))
+	}
+	return nil
+}
+
+// clipLines cuts text to at most n lines so that panes keep their height in small terminals
+func clipLines(text string, n int) string {
+	lines := strings.Split(text, "\n")
+	if len(lines) <= n {
+		return text
+	}
+	return strings.Join(lines[:max(0, n)], "\n")
+}
+
+// renderPanes arranges the panes according to the layout
+func (m Model) renderPanes(l paneLayout) string {
+	worktrees := m.renderWorktreesPanel(l.worktreesWidth, l.worktreesHeight)
+	if l.hideRepos {
+		return worktrees
+	}
+	repos := m.renderReposPanel(l.reposWidth, l.reposHeight)
+	if l.stacked {
+		return lipgloss.JoinVertical(lipgloss.Left, repos, worktrees)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, repos, worktrees)
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/michael-rose/workman/internal/config"
+	"github.com/michael-rose/workman/internal/state"
+)
+
+func layoutModel(width, height int, cfg config.Config) Model {
+	return Model{width: width, height: height, state: &state.AppState{Config: &cfg}}
+}
+
+func TestLayoutHorizontal(t *testing.T) {
+	m := layoutModel(100, 30, config.Config{Layout: layoutAuto, Split: 30})
+	l := m.layout()
+	if l.stacked || l.reposWidth != 26 || l.worktreesWidth != 66 || l.reposHeight != 24 {
+		t.Fatalf("unexpected layout %+v", l)
+	}
+
+	if pane, row, ok := l.paneAt(5, 3); !ok || pane != state.ReposPane || row != 1 {
+		t.Errorf("paneAt(5, 3) = %s, %d, %v", pane, row, ok)
+	}
+	if pane, _, ok := l.paneAt(28, 3); !ok || pane != state.WorktreesPane {
+		t.Errorf("paneAt(28, 3) = %s, %v", pane, ok)
+	}
+	if !l.onDivider(27, 10) || l.onDivider(20, 10) {
+		t.Error("divider not found at the border between the panes")
+	}
+
+	// Dragging the divider to a column moves the split there
+	if split := m.splitAt(37, 10); split != 40 {
+		t.Errorf("splitAt(37) = %d, want 40", split)
+	}
+	if split := m.splitAt(0, 10); split != minSplit {
+		t.Errorf("splitAt(0) = %d, want %d", split, minSplit)
+	}
+}
+
+func TestLayoutStacked(t *testing.T) {
+	// Narrow terminals stack the panes in the auto layout
+	m := layoutModel(60, 30, config.Config{Layout: layoutAuto, Split: 40})
+	l := m.layout()
+	if !l.stacked || l.reposWidth != 56 || l.reposHeight != 8 || l.worktreesHeight != 14 {
+		t.Fatalf("unexpected layout %+v", l)
+	}
+	if pane, row, ok := l.paneAt(5, 12); !ok || pane != state.WorktreesPane || row != 0 {
+		t.Errorf("paneAt(5, 12) = %s, %d, %v", pane, row, ok)
+	}
+	if !l.onDivider(5, 9) {
+		t.Error("divider not found between the stacked panes")
+	}
+
+	// The horizontal layout is kept when configured
+	m.state.Config.Layout = layoutHorizontal
+	if m.layout().stacked {
+		t.Error("horizontal layout stacked")
+	}
+
+	// Short terminals fit only without the repositories pane
+	m = layoutModel(60, 12, config.Config{Layout: layoutVertical})
+	if m.fits() {
+		t.Error("stacked panes fit into 12 lines")
+	}
+	m.state.Config.HideRepos = true
+	if !m.fits() {
+		t.Error("worktrees pane does not fit into 12 lines")
+	}
+}
+
+func TestLayoutHiddenRepos(t *testing.T) {
+	m := layoutModel(100, 30, config.Config{Split: 40, HideRepos: true})
+	l := m.layout()
+	if l.worktreesWidth != 96 || l.onDivider(37, 10) {
+		t.Fatalf("unexpected layout %+v", l)
+	}
+	if pane, _, ok := l.paneAt(5, 3); !ok || pane != state.WorktreesPane {
+		t.Errorf("paneAt(5, 3) = %s, %v", pane, ok)
+	}
+}
@@ -1,22 +1,27 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/atotto/clipboard"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/michael-rose/workman/internal/config"
 	"github.com/michael-rose/workman/internal/git"
+	"github.com/michael-rose/workman/internal/runs"
 	"github.com/michael-rose/workman/internal/state"
+	"github.com/michael-rose/workman/internal/watch"
 )
 
 type Model struct {
 	state                   *state.AppState
+	keys                    KeyMap
 	width                   int
 	height                  int
 	dialogType              DialogType
@@ -24,6 +29,24 @@ type Model struct {
 	addWorktreeDialog       AddWorktreeDialog
 	confirmDeleteDialog     ConfirmDeleteDialog
 	confirmDeleteRepoDialog ConfirmDeleteRepositoryDialog
+	diffView                DiffView
+	stashView               StashView
+	syncConflictDialog      SyncConflictDialog
+	confirmPushDialog       ConfirmPushDialog
+	outputView              OutputView
+	cleanupView             CleanupView
+	overviewView            OverviewView
+	helpView                HelpView
+	paletteView             PaletteView
+	actionPicker            ActionPicker
+	runsView                RunsView
+	runLog                  *runs.Log               // scripts and actions run by workman
+	cancelScript            context.CancelCauseFunc // stops the running post-create script
+	scriptDone              chan struct{}           // closed when the running post-create script has exited
+	watcher                 *watch.Watcher          // nil if watching is disabled or unavailable
+	lastClick               click                   // for detecting double-clicks
+	dragging                bool                    // the divider between the panes is being dragged
+	scroll                  *scrollState            // shared so that rendering can keep the selection visible
 	errorMsg                string
 	successMsg              string
 }
@@ -43,20 +66,42 @@ type editorFinishedMsg struct {
 	tempPath     string
 }
 
-func NewModel(appState *state.AppState) Model {
+func NewModel(appState *state.AppState, keys KeyMap) Model {
 	m := Model{
 		state:      appState,
+		keys:       keys,
+		scroll:     &scrollState{},
+		runLog:     newRunLog(),
 		width:      80,
 		height:     24,
 		dialogType: DialogNone,
 	}
+	if appState.Config.Watch {
+		// Without a watcher the data is refreshed on navigation and with r/R only
+		if watcher, err := watch.New(watchDebounce, appState.Config.WatchMaxDirs); err == nil {
+			m.watcher = watcher
+		}
+	}
 	// Load initial worktrees
 	m = m.loadWorktrees()
 	return m
 }
 
 func (m Model) Init() tea.Cmd {
-	return nil
+	// Load the other repositories in the background and start watching for changes
+	cmds := []tea.Cmd{m.refreshAllCmd(false), m.waitForChange(), m.waitForRun()}
+	if repo := m.state.GetSelectedRepo(); repo != nil {
+		cmds = append(cmds, m.watchRepoCmd(*repo, m.state.Worktrees))
+	}
+	return tea.Batch(cmds...)
+}
+
+// Close releases resources held after the program has quit; a running post-create script is killed
+func (m Model) Close() {
+	m.stopScript()
+	if m.watcher != nil {
+		_ = m.watcher.Close()
+	}
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -64,16 +109,82 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.diffView.SetSize(msg.Width, msg.Height)
+		m.outputView.SetSize(msg.Width, msg.Height)
+		m.overviewView.SetSize(msg.Width, msg.Height)
+		m.helpView.SetSize(msg.Width, msg.Height)
+		m.paletteView.SetSize(msg.Width, msg.Height)
+		m.runsView.SetSize(msg.Width, msg.Height)
 		return m, nil
 
 	case errorMsg:
+		// A pending progress message would hide the error
 		m.errorMsg = msg.err
+		m.successMsg = ""
 		return m, nil
 
 	case successMsg:
 		m.successMsg = msg.msg
 		return m, nil
 
+	case setupStepMsg:
+		return m.handleSetupStep(msg)
+
+	case deleteWarningsMsg:
+		return m.handleDeleteWarnings(msg)
+
+	case scriptOutputMsg:
+		return m.handleScriptOutput(msg)
+
+	case syncResultMsg:
+		return m.handleSyncResult(msg)
+
+	case bulkSyncResultMsg:
+		return m.handleBulkSyncResult(msg)
+
+	case runFinishedMsg:
+		return m.handleRunFinished(msg)
+
+	case reportMsg:
+		return m.handleReport(msg)
+
+	case repoLoadedMsg:
+		return m.handleRepoLoaded(msg)
+
+	case worktreeLoadedMsg:
+		return m.handleWorktreeLoaded(msg)
+
+	case fsChangedMsg:
+		return m.handleFSChanged(msg)
+
+	case overviewLoadedMsg:
+		m.overviewView.SetRows(msg.rows, msg.errors)
+		return m, nil
+
+	case showStashDiffMsg:
+		diffView, err := NewStashDiffView(msg.stash, m.stashView.worktreePath, DialogStash, m.width, m.height)
+		if err != nil {
+			m.errorMsg = fmt.Sprintf("Failed to load stash diff: %v", err)
+			return m, nil
+		}
+		m.diffView = diffView
+		m.dialogType = DialogDiff
+		return m, nil
+
+	case actionFinishedMsg:
+		if msg.err != nil {
+			m.errorMsg = fmt.Sprintf("%s failed: %v", msg.name, msg.err)
+		} else {
+			m.successMsg = fmt.Sprintf("%s finished", msg.name)
+		}
+		return m, nil
+
+	case editorClosedMsg:
+		if msg.err != nil {
+			m.errorMsg = fmt.Sprintf("Editor failed: %v", msg.err)
+		}
+		return m, nil
+
 	case editorFinishedMsg:
 		if msg.tempPath != "" {
 			defer func() {
@@ -103,7 +214,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 			m.errorMsg = ""
-			return m, showSuccess("Notes saved")
+			m, cmd := m.refreshWorktree()
+			// Show the new notes right away if the worktree is still selected
+			if repo := m.state.GetSelectedRepo(); repo != nil && repo.Name == msg.repoName &&
+				m.state.SelectedWTIndex < len(m.state.Worktrees) && m.state.Worktrees[m.state.SelectedWTIndex].Name == msg.worktreeName {
+				m.state.Notes = value
+			}
+			return m, tea.Batch(cmd, showSuccess("Notes saved"))
 		case editScriptTarget:
 			if err := config.SaveRepoScript(msg.repoName, value); err != nil {
 				m.errorMsg = fmt.Sprintf("Failed to save script: %v", err)
@@ -111,7 +228,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 			m.errorMsg = ""
-			return m, showSuccess("Post-create script saved")
+			var cmd tea.Cmd
+			if repo := m.state.GetSelectedRepo(); repo != nil && repo.Name == msg.repoName {
+				cmd = m.refreshRepoCmd(*repo, true)
+			}
+			return m, tea.Batch(cmd, showSuccess("Post-create script saved"))
 		}
 
 		return m, nil
@@ -122,150 +243,46 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleDialogKeys(msg)
 		}
 
-		// Normal mode key handling
-		switch msg.String() {
-		case "ctrl+c", "q":
-			return m, tea.Quit
-
-		case "tab":
-			m.state.TogglePane()
-			return m, nil
-
-		case "h":
-			m.state.ActivePane = state.ReposPane
-			return m, nil
-
-		case "l":
-			m.state.ActivePane = state.WorktreesPane
-			return m, nil
-
-		case "up", "k":
-			if m.state.ActivePane == state.ReposPane {
-				m.state.PrevRepo()
-				m = m.loadWorktrees()
-			} else {
-				m.state.PrevWorktree()
-			}
-			return m, nil
-
-		case "down", "j":
-			if m.state.ActivePane == state.ReposPane {
-				m.state.NextRepo()
-				m = m.loadWorktrees()
-			} else {
-				m.state.NextWorktree()
-			}
-			return m, nil
-
-		case "y":
-			if m.state.ActivePane == state.WorktreesPane {
-				if len(m.state.Worktrees) > 0 && m.state.GetSelectedRepo() != nil {
-					return m.yankWorktreeCommand()
-				}
-			}
-			return m, nil
-
-		case "n":
-			if m.state.ActivePane == state.WorktreesPane {
-				if len(m.state.Worktrees) > 0 && m.state.GetSelectedRepo() != nil {
-					selectedWT := m.state.Worktrees[m.state.SelectedWTIndex]
-					repo := m.state.GetSelectedRepo()
-					currentNotes, err := config.GetWorktreeNotes(repo.Name, selectedWT.Name)
-					if err != nil {
-						return m, showError(fmt.Sprintf("Failed to load notes: %v", err))
-					}
-					return m.openEditor(editNotesTarget, repo.Name, selectedWT.Name, currentNotes)
-				}
-			}
-			return m, nil
-
-		case "s":
-			if m.state.ActivePane == state.ReposPane {
-				if repo := m.state.GetSelectedRepo(); repo != nil {
-					currentScript, err := config.GetRepoScript(repo.Name)
-					if err != nil {
-						return m, showError(fmt.Sprintf("Failed to load script: %v", err))
-					}
-					return m.openEditor(editScriptTarget, repo.Name, "", currentScript)
-				}
-			}
-			return m, nil
-
-		case "+":
-			switch m.state.ActivePane {
-			case state.ReposPane:
-				// Show add repo dialog
-				m.dialogType = DialogAddRepo
-				m.addRepoDialog = NewAddRepoDialog()
-				m.errorMsg = ""
-				m.successMsg = ""
-			case state.WorktreesPane:
-				// Show add worktree dialog (only if a repo is selected)
-				if repo := m.state.GetSelectedRepo(); repo != nil {
-					// Fetch branches for autocomplete
-					branches, err := git.ListBranches(repo.Path)
-					if err != nil {
-						branches = []string{} // If fetch fails, continue with empty list
-					}
-
-					m.dialogType = DialogAddWorktree
-					m.addWorktreeDialog = NewAddWorktreeDialog(branches)
-					m.errorMsg = ""
-					m.successMsg = ""
-				}
-			}
-			return m, nil
+		// Esc cancels a post-create script whose output is not shown
+		if msg.String() == "esc" && m.cancelScript != nil {
+			return m.cancelPostCreateScript()
+		}
 
-		case "-":
-			// Handle deletion based on active pane
-			switch m.state.ActivePane {
-			case state.ReposPane:
-				// Delete repository
-				if len(m.state.Config.Repositories) > 0 {
-					selectedRepo := m.state.GetSelectedRepo()
-					if selectedRepo != nil {
-						m.dialogType = DialogConfirmDeleteRepo
-						m.confirmDeleteRepoDialog = NewConfirmDeleteRepositoryDialog(selectedRepo.Name)
-						m.errorMsg = ""
-						m.successMsg = ""
-					}
-				}
-			case state.WorktreesPane:
-				// Delete worktree
-				if len(m.state.Worktrees) > 0 && m.state.GetSelectedRepo() != nil {
-					selectedWT := m.state.Worktrees[m.state.SelectedWTIndex]
-					// Don't allow deleting the main worktree (first one)
-					if m.state.SelectedWTIndex > 0 {
-						m.dialogType = DialogConfirmDelete
-						m.confirmDeleteDialog = NewConfirmDeleteDialog(selectedWT.Name, selectedWT.Branch)
-						m.errorMsg = ""
-						m.successMsg = ""
-					}
-				}
-			}
-			return m, nil
+		// Normal mode key handling
+		return m.runKey(msg)
 
-		case "enter":
-			if m.state.ActivePane == state.WorktreesPane &&
-				len(m.state.Worktrees) > 0 &&
-				m.state.GetSelectedRepo() != nil {
-				// Execute enter_script
-				if err := m.executeScript(m.state.Config.EnterScript); err != nil {
-					m.errorMsg = fmt.Sprintf("Enter key: %v. Set 'enter_script' to a script file path in config.toml", err)
-					m.successMsg = ""
-				} else {
-					m.successMsg = "Script executed"
-					m.errorMsg = ""
-				}
-			}
-			return m, nil
-		}
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
 	}
 
 	return m, nil
 }
 
 func (m Model) handleDialogKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Views handle their own keys
+	switch m.dialogType {
+	case DialogDiff:
+		return m.handleDiffKeys(msg)
+	case DialogStash:
+		return m.handleStashKeys(msg)
+	case DialogSyncConflict:
+		return m.handleSyncConflictKeys(msg)
+	case DialogOutput:
+		return m.handleOutputKeys(msg)
+	case DialogCleanup:
+		return m.handleCleanupKeys(msg)
+	case DialogOverview:
+		return m.handleOverviewKeys(msg)
+	case DialogHelp:
+		return m.handleHelpKeys(msg)
+	case DialogPalette:
+		return m.handlePaletteKeys(msg)
+	case DialogActionPicker:
+		return m.handleActionPickerKeys(msg)
+	case DialogRuns:
+		return m.handleRunsKeys(msg)
+	}
+
 	switch msg.String() {
 	case "ctrl+c":
 		return m, tea.Quit
@@ -281,7 +298,7 @@ func (m Model) handleDialogKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// "n" only cancels confirmation dialogs (means "no")
 		// For other dialogs, it's just a regular character
 		switch m.dialogType {
-		case DialogConfirmDelete, DialogConfirmDeleteRepo:
+		case DialogConfirmDelete, DialogConfirmDeleteRepo, DialogConfirmPush:
 			m.dialogType = DialogNone
 			m.errorMsg = ""
 			m.successMsg = ""
@@ -293,9 +310,20 @@ func (m Model) handleDialogKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// For other dialogs, fall through to pass "y" to the input handler
 		switch m.dialogType {
 		case DialogConfirmDelete:
+			if m.confirmDeleteDialog.Checking() {
+				return m, nil
+			}
+			if len(m.confirmDeleteDialog.worktrees) > 0 {
+				return m.deleteMarkedWorktrees()
+			}
 			return m.deleteWorktree()
 		case DialogConfirmDeleteRepo:
 			return m.deleteRepository()
+		case DialogConfirmPush:
+			items := m.confirmPushDialog.items
+			m.dialogType = DialogNone
+			m.successMsg = fmt.Sprintf("Pushing %d branch(es)...", len(items))
+			return m, pushCmd("Push all worktrees", items)
 		}
 
 	case "ctrl+s":
@@ -326,6 +354,93 @@ func (m Model) handleDialogKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m Model) handleDiffKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q":
+		m.dialogType = m.diffView.parent
+		m.errorMsg = ""
+		return m, nil
+	}
+
+	m.errorMsg = ""
+	cmd := m.diffView.Update(msg)
+	return m, cmd
+}
+
+func (m Model) handleOutputKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.outputView.running {
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			return m.cancelPostCreateScript()
+		case "q", "enter":
+			return m, nil
+		}
+	}
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q", "enter":
+		m.dialogType = m.outputView.parent
+		return m, nil
+	}
+
+	cmd := m.outputView.Update(msg)
+	return m, cmd
+}
+
+func (m Model) handleStashKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q":
+		if !m.stashView.Capturing() {
+			m.dialogType = DialogNone
+			m.errorMsg = ""
+			return m.refreshWorktree()
+		}
+	}
+
+	m.errorMsg = ""
+	m.successMsg = ""
+	cmd := m.stashView.Update(msg)
+	return m, cmd
+}
+
+func (m Model) openDiffView() (tea.Model, tea.Cmd) {
+	repo := m.state.GetSelectedRepo()
+	selectedWT := m.state.Worktrees[m.state.SelectedWTIndex]
+	baseBranch, _ := git.ResolveBaseBranch(repo.Path, repo.BaseBranch, repo.Type == "remote")
+
+	diffView, err := NewDiffView(selectedWT.Name, selectedWT.Path, baseBranch, m.width, m.height)
+	if err != nil {
+		return m, showError(fmt.Sprintf("Failed to load diff: %v", err))
+	}
+
+	m.diffView = diffView
+	m.dialogType = DialogDiff
+	m.errorMsg = ""
+	m.successMsg = ""
+	return m, nil
+}
+
+func (m Model) openStashView() (tea.Model, tea.Cmd) {
+	selectedWT := m.state.Worktrees[m.state.SelectedWTIndex]
+
+	stashView, err := NewStashView(selectedWT.Name, selectedWT.Path, selectedWT.Branch)
+	if err != nil {
+		return m, showError(fmt.Sprintf("Failed to load stashes: %v", err))
+	}
+
+	m.stashView = stashView
+	m.dialogType = DialogStash
+	m.errorMsg = ""
+	m.successMsg = ""
+	return m, nil
+}
+
 func (m Model) openEditor(target editTarget, repoName, worktreeName, content string) (tea.Model, tea.Cmd) {
 	var prefix string
 	switch target {
@@ -430,6 +545,11 @@ func (m Model) saveRepository() (tea.Model, tea.Cmd) {
 		URL:  repoURL,
 	}
 
+	// Fetch LFS objects for cloned repositories using LFS
+	if repoType == "remote" && git.UsesLFS(repoPath) && git.LFSAvailable() {
+		newRepo.FetchLFS = true
+	}
+
 	// Add to config
 	m.state.Config.Repositories = append(m.state.Config.Repositories, newRepo)
 
@@ -438,15 +558,20 @@ func (m Model) saveRepository() (tea.Model, tea.Cmd) {
 		return m, showError(fmt.Sprintf("Failed to save config: %v", err))
 	}
 
-	// Select the newly added repository and load its worktrees
+	// Select the newly added repository and load its worktrees in the background
 	m.state.SelectedRepoIndex = len(m.state.Config.Repositories) - 1
-	m = m.loadWorktrees()
+	m, loadCmd := m.showRepo()
 
 	// Close dialog
 	m.dialogType = DialogNone
 	m.errorMsg = ""
 
-	return m, showSuccess(fmt.Sprintf("Repository '%s' added successfully", name))
+	if newRepo.FetchLFS {
+		m.successMsg = fmt.Sprintf("Repository '%s' added, fetching LFS objects...", name)
+		return m, tea.Batch(loadCmd, pullLFSCmd(name, repoPath))
+	}
+
+	return m, tea.Batch(loadCmd, showSuccess(fmt.Sprintf("Repository '%s' added successfully", name)))
 }
 
 // sanitizeRepoName converts a repository name to a safe directory name
@@ -488,9 +613,15 @@ func (m Model) saveWorktree() (tea.Model, tea.Cmd) {
 	branch := m.addWorktreeDialog.GetBranchName()
 
 	// Create worktree in configured root directory
-	isRemote := repo.Type == "remote"
-	rootDir := m.state.Config.RootDirectory
-	if err := git.AddWorktree(repo.Path, rootDir, repo.Name, branch, isRemote); err != nil {
+	baseBranch, err := git.ResolveBaseBranch(repo.Path, repo.BaseBranch, repo.Type == "remote")
+	if err != nil {
+		return m, showError(fmt.Sprintf("Failed to determine base branch: %v", err))
+	}
+	worktreePath, err := m.newWorktreePath(repo, branch)
+	if err != nil {
+		return m, showError(fmt.Sprintf("Failed to apply worktree_path_template: %v", err))
+	}
+	if err := git.AddWorktree(repo.Path, worktreePath, branch, baseBranch); err != nil {
 		return m, showError(fmt.Sprintf("Failed to create worktree: %v", err))
 	}
 
@@ -500,44 +631,110 @@ func (m Model) saveWorktree() (tea.Model, tea.Cmd) {
 		return m, showError(fmt.Sprintf("Failed to list worktrees: %v", err))
 	}
 	m.state.Worktrees = worktrees
+	m.cacheWorktrees(repo.Name, worktrees)
 
 	// Select the newly created worktree
+	var newWorktreePath string
 	for i, wt := range worktrees {
 		if wt.Branch == branch {
 			m.state.SelectedWTIndex = i
+			newWorktreePath = wt.Path
 			break
 		}
 	}
 
-	// Execute post-create script if configured
+	// Load post-create script if configured
 	script, err := config.GetRepoScript(repo.Name)
 	if err != nil {
 		return m, showError(fmt.Sprintf("Failed to load post-create script: %v", err))
 	}
-	if script != "" {
-		// Find the newly created worktree
-		var newWorktreePath string
+
+	// Close dialog
+	m.dialogType = DialogNone
+	m.errorMsg = ""
+	m, loadCmd := m.showWorktree()
+
+	if newWorktreePath == "" {
+		return m, tea.Batch(loadCmd, showSuccess("Worktree created successfully"))
+	}
+
+	// Run submodule, LFS and post-create script setup in the background
+	m, setupCmd := m.continueSetup(worktreeSetup{
+		repoName:       repo.Name,
+		repoPath:       repo.Path,
+		worktreePath:   newWorktreePath,
+		initSubmodules: repo.InitSubmodules,
+		fetchLFS:       repo.FetchLFS,
+		script:         script,
+		timeout:        time.Duration(m.state.Config.PostCreateTimeout) * time.Second,
+		runLog:         m.runLog,
+	})
+	return m, tea.Batch(loadCmd, setupCmd)
+}
+
+// deleteWarningsMsg is sent when the data that deleting worktrees would lose has been checked
+type deleteWarningsMsg struct {
+	key      string
+	warnings []string
+}
+
+// deleteKey identifies the worktrees of a delete confirmation
+func deleteKey(worktrees []state.Worktree) string {
+	paths := make([]string, len(worktrees))
+	for i, wt := range worktrees {
+		paths[i] = wt.Path
+	}
+	return strings.Join(paths, "\x00")
+}
+
+// deleteWarningsCmd checks in the background what deleting worktrees would lose
+// The LFS check may contact the remote, so it must not block the UI
+func deleteWarningsCmd(worktrees []state.Worktree) tea.Cmd {
+	return func() tea.Msg {
+		var warnings []string
 		for _, wt := range worktrees {
-			if wt.Branch == branch {
-				newWorktreePath = wt.Path
-				break
+			for _, warning := range deleteWarnings(wt) {
+				if len(worktrees) > 1 {
+					warning = fmt.Sprintf("%s: %s", wt.Name, warning)
+				}
+				warnings = append(warnings, warning)
 			}
 		}
+		return deleteWarningsMsg{key: deleteKey(worktrees), warnings: warnings}
+	}
+}
 
-		if newWorktreePath != "" {
-			if err := git.ExecutePostCreateScript(script, repo.Path, newWorktreePath); err != nil {
-				m.dialogType = DialogNone
-				m.errorMsg = ""
-				return m, showError(fmt.Sprintf("Worktree created but script failed: %v", err))
+// handleDeleteWarnings shows the warnings in the delete confirmation they were checked for
+func (m Model) handleDeleteWarnings(msg deleteWarningsMsg) (tea.Model, tea.Cmd) {
+	if m.dialogType == DialogConfirmDelete && m.confirmDeleteDialog.key == msg.key {
+		m.confirmDeleteDialog.warnings = msg.warnings
+		m.confirmDeleteDialog.checking = false
+	}
+	return m, nil
+}
+
+// deleteWarnings lists data of a worktree that would be lost by deleting it
+func deleteWarnings(wt state.Worktree) []string {
+	var warnings []string
+	dirty, err := git.DirtySubmodules(wt.Path)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("Could not check submodules: %v", err))
+	}
+	for _, path := range dirty {
+		warnings = append(warnings, fmt.Sprintf("Submodule '%s' has changes that will be lost", path))
+	}
+	if git.UsesLFS(wt.Path) && git.LFSAvailable() {
+		if remote, err := git.PushRemote(wt.Path, wt.Branch); err == nil {
+			unpushed, err := git.UnpushedLFSObjects(wt.Path, remote, wt.Branch)
+			if err == nil && len(unpushed) > 0 {
+				warnings = append(warnings, fmt.Sprintf("%d LFS object(s) have not been pushed to %s", len(unpushed), remote))
 			}
 		}
 	}
-
-	// Close dialog
-	m.dialogType = DialogNone
-	m.errorMsg = ""
-
-	return m, showSuccess("Worktree created successfully")
+	if wt.Locked {
+		warnings = append(warnings, "Worktree is locked - unlock it with 'L' first")
+	}
+	return warnings
 }
 
 func (m Model) deleteWorktree() (tea.Model, tea.Cmd) {
@@ -573,17 +770,19 @@ func (m Model) deleteWorktree() (tea.Model, tea.Cmd) {
 		return m, showError(fmt.Sprintf("Failed to list worktrees: %v", err))
 	}
 	m.state.Worktrees = worktrees
+	m.cacheWorktrees(repo.Name, worktrees)
 
 	// Adjust selected index if needed
 	if m.state.SelectedWTIndex >= len(m.state.Worktrees) && len(m.state.Worktrees) > 0 {
 		m.state.SelectedWTIndex = len(m.state.Worktrees) - 1
 	}
+	m.state.Cache.InvalidateWorktree(selectedWT.Path)
 
 	// Close dialog
 	m.dialogType = DialogNone
 	m.errorMsg = ""
 
-	return m, nil
+	return m.showWorktree()
 }
 
 func (m Model) deleteRepository() (tea.Model, tea.Cmd) {
@@ -641,6 +840,7 @@ func (m Model) deleteRepository() (tea.Model, tea.Cmd) {
 		_ = config.DeleteWorktreeNotes(repo.Name, wt.Name)
 	}
 	_ = config.DeleteRepoScript(repo.Name)
+	m.state.Cache.InvalidateRepo(repo.Name)
 
 	// Remove repository from config
 	repoIndex := m.state.SelectedRepoIndex
@@ -661,14 +861,14 @@ func (m Model) deleteRepository() (tea.Model, tea.Cmd) {
 		m.state.SelectedRepoIndex = len(m.state.Config.Repositories) - 1
 	}
 
-	// Reload worktrees for the new selected repository
-	m = m.loadWorktrees()
+	// Show the worktrees of the new selected repository
+	m, loadCmd := m.showRepo()
 
 	// Close dialog
 	m.dialogType = DialogNone
 	m.errorMsg = ""
 
-	return m, showSuccess(fmt.Sprintf("Repository '%s' deleted successfully", repo.Name))
+	return m, tea.Batch(loadCmd, showSuccess(fmt.Sprintf("Repository '%s' deleted successfully", repo.Name)))
 }
 
 func (m Model) yankWorktreeCommand() (tea.Model, tea.Cmd) {
@@ -682,20 +882,11 @@ func (m Model) yankWorktreeCommand() (tea.Model, tea.Cmd) {
 	}
 
 	selectedWT := m.state.Worktrees[m.state.SelectedWTIndex]
-
-	// Get template from config
-	template := m.state.Config.YankTemplate
-	if template == "" {
-		template = "${worktree_path}"
+	result, err := m.yankText(repo, selectedWT)
+	if err != nil {
+		return m, showError(fmt.Sprintf("Failed to apply yank_template: %v", err))
 	}
 
-	// Perform substitutions
-	result := template
-	result = strings.ReplaceAll(result, "${repo_name}", repo.Name)
-	result = strings.ReplaceAll(result, "${branch_name}", selectedWT.Branch)
-	result = strings.ReplaceAll(result, "${worktree_path}", selectedWT.Path)
-	result = strings.ReplaceAll(result, "${worktree_name}", selectedWT.Name)
-
 	// Copy to clipboard
 	if err := clipboard.WriteAll(result); err != nil {
 		return m, showError(fmt.Sprintf("Failed to copy: %v", err))
@@ -705,49 +896,58 @@ func (m Model) yankWorktreeCommand() (tea.Model, tea.Cmd) {
 	return m, showSuccess("Copied to clipboard")
 }
 
+// yankText applies the yank template to a worktree
+func (m Model) yankText(repo *config.Repository, wt state.Worktree) (string, error) {
+	text := m.state.Config.YankTemplate
+	if text == "" {
+		text = "${worktree_path}"
+	}
+	return renderTemplate(text, m.state.Config, repo, wt)
+}
+
+// loadWorktrees loads the worktrees of the selected repository and updates the cache
 func (m Model) loadWorktrees() Model {
+	m.state.ClearMarks()
+	m.state.SelectedWTIndex = 0
 	repo := m.state.GetSelectedRepo()
 	if repo == nil {
 		m.state.Worktrees = []state.Worktree{}
+		m.state.Submodules = []state.Submodule{}
+		m.state.Details = nil
 		return m
 	}
 
-	// Only try to load worktrees for local repos or if path exists
-	if _, err := os.Stat(repo.Path); os.IsNotExist(err) {
-		m.state.Worktrees = []state.Worktree{}
-		return m
-	}
+	data := loadRepoData(*repo)
+	m.state.Cache.SetRepo(repo.Name, data)
+	m.state.Worktrees = data.Worktrees
+	return m.loadWorktreeDetails()
+}
 
-	worktrees, err := git.ListWorktrees(repo.Path)
-	if err != nil {
-		// Failed to load worktrees, just set empty list
-		m.state.Worktrees = []state.Worktree{}
+// loadWorktreeDetails loads the information shown for the selected worktree and updates the cache
+func (m Model) loadWorktreeDetails() Model {
+	m.state.Submodules = []state.Submodule{}
+	m.state.Notes = ""
+	m.state.Details = nil
+	repo := m.state.GetSelectedRepo()
+	if repo == nil || len(m.state.Worktrees) == 0 || m.state.SelectedWTIndex >= len(m.state.Worktrees) {
 		return m
 	}
 
-	m.state.Worktrees = worktrees
-	m.state.SelectedWTIndex = 0
+	selectedWT := m.state.Worktrees[m.state.SelectedWTIndex]
+	data := loadWorktreeData(*repo, selectedWT, m.state.ShowDetails)
+	m.state.Cache.SetWorktree(selectedWT.Path, data)
+	m.state.Submodules = data.Submodules
+	m.state.Notes = data.Notes
+	m.state.Details = data.Details
 	return m
 }
 
 func (m Model) View() string {
-	if m.width < 40 || m.height < 10 {
+	if !m.fits() {
 		return "Terminal too small. Please resize."
 	}
 
-	// Calculate panel dimensions (split view: 40% left, 60% right)
-	leftWidth := m.width*40/100 - 4
-	rightWidth := m.width*60/100 - 4
-	panelHeight := m.height - 6
-
-	// Render left panel (repositories)
-	leftPanel := m.renderReposPanel(leftWidth, panelHeight)
-
-	// Render right panel (worktrees)
-	rightPanel := m.renderWorktreesPanel(rightWidth, panelHeight)
-
-	// Combine panels side by side
-	panels := lipgloss.JoinHorizontal(lipgloss.Top, leftPanel, rightPanel)
+	panels := m.renderPanes(m.layout())
 
 	// Render help text
 	help := m.renderHelp()
@@ -755,26 +955,8 @@ func (m Model) View() string {
 	mainView := lipgloss.JoinVertical(lipgloss.Left, panels, help)
 
 	// Show success/error feedback if no dialog is active
-	if m.dialogType == DialogNone {
-		if m.successMsg != "" {
-			successStyle := lipgloss.NewStyle().
-				Foreground(lipgloss.AdaptiveColor{Light: "#047857", Dark: "#10B981"}).
-				Background(lipgloss.AdaptiveColor{Light: "#D1FAE5", Dark: "#064E3B"}).
-				Bold(true).
-				Padding(0, 2).
-				Width(m.width - 4)
-			feedback := successStyle.Render("✓ " + m.successMsg)
-			mainView = lipgloss.JoinVertical(lipgloss.Left, feedback, mainView)
-		} else if m.errorMsg != "" {
-			errorStyle := lipgloss.NewStyle().
-				Foreground(lipgloss.AdaptiveColor{Light: "#B91C1C", Dark: "#EF4444"}).
-				Background(lipgloss.AdaptiveColor{Light: "#FEE2E2", Dark: "#7F1D1D"}).
-				Bold(true).
-				Padding(0, 2).
-				Width(m.width - 4)
-			feedback := errorStyle.Render("✗ " + m.errorMsg)
-			mainView = lipgloss.JoinVertical(lipgloss.Left, feedback, mainView)
-		}
+	if feedback := m.renderFeedback(); feedback != "" {
+		mainView = lipgloss.JoinVertical(lipgloss.Left, feedback, mainView)
 	}
 
 	// Show dialog if active
@@ -789,15 +971,35 @@ func (m Model) View() string {
 			dialog = m.confirmDeleteDialog.View()
 		case DialogConfirmDeleteRepo:
 			dialog = m.confirmDeleteRepoDialog.View()
+		case DialogDiff:
+			dialog = m.diffView.View()
+		case DialogStash:
+			dialog = m.stashView.View()
+		case DialogSyncConflict:
+			dialog = m.syncConflictDialog.View()
+		case DialogConfirmPush:
+			dialog = m.confirmPushDialog.View()
+		case DialogOutput:
+			dialog = m.outputView.View()
+		case DialogCleanup:
+			dialog = m.cleanupView.View()
+		case DialogOverview:
+			dialog = m.overviewView.View()
+		case DialogHelp:
+			dialog = m.helpView.View()
+		case DialogPalette:
+			dialog = m.paletteView.View()
+		case DialogActionPicker:
+			dialog = m.actionPicker.View()
+		case DialogRuns:
+			dialog = m.runsView.View()
 		}
 
-		// Add error message if present
+		// Add error or success message if present
 		if m.errorMsg != "" {
-			errorStyle := lipgloss.NewStyle().
-				Foreground(lipgloss.AdaptiveColor{Light: "#B91C1C", Dark: "#EF4444"}).
-				Bold(true).
-				Padding(0, 2)
 			dialog = lipgloss.JoinVertical(lipgloss.Left, dialog, errorStyle.Render("Error: "+m.errorMsg))
+		} else if m.successMsg != "" {
+			dialog = lipgloss.JoinVertical(lipgloss.Left, dialog, successStyle.Render("✓ "+m.successMsg))
 		}
 
 		// Center the dialog
@@ -805,13 +1007,27 @@ func (m Model) View() string {
 			lipgloss.Center, lipgloss.Center,
 			dialog,
 			lipgloss.WithWhitespaceChars("░"),
-			lipgloss.WithWhitespaceForeground(lipgloss.AdaptiveColor{Light: "#E5E7EB", Dark: "#1F2937"}),
+			lipgloss.WithWhitespaceForeground(backdropColor),
 		)
 	}
 
 	return mainView
 }
 
+// renderFeedback renders the success or error message shown above the panels, if any
+func (m Model) renderFeedback() string {
+	if m.dialogType != DialogNone {
+		return ""
+	}
+	if m.successMsg != "" {
+		return successBanner.Width(m.width - 4).Render("✓ " + m.successMsg)
+	}
+	if m.errorMsg != "" {
+		return errorBanner.Width(m.width - 4).Render("✗ " + m.errorMsg)
+	}
+	return ""
+}
+
 func (m Model) renderReposPanel(width, height int) string {
 	isActive := m.state.ActivePane == state.ReposPane
 	style := panelStyle
@@ -828,17 +1044,17 @@ func (m Model) renderReposPanel(width, height int) string {
 	} else {
 		for i, repo := range m.state.Config.Repositories {
 			scriptIndicator := ""
-			hasScript, err := config.HasRepoScript(repo.Name)
-			if err == nil && hasScript {
+			if data, ok := m.state.Cache.Repo(repo.Name); ok && data.HasScript {
 				scriptIndicator = " 📜"
 			}
-			itemText := fmt.Sprintf("%s (%s)%s", repo.Name, repo.Type, scriptIndicator)
+			itemText := truncate(fmt.Sprintf("%s (%s)%s", repo.Name, repo.Type, scriptIndicator), width-4)
 			if isActive && i == m.state.SelectedRepoIndex {
 				items = append(items, selectedItemStyle.Render("> "+itemText))
 			} else {
 				items = append(items, itemStyle.Render("  "+itemText))
 			}
 		}
+		items = m.scroll.repos.window(items, m.state.SelectedRepoIndex, height-1)
 	}
 
 	content := lipgloss.JoinVertical(lipgloss.Left, header, strings.Join(items, "\n"))
@@ -859,7 +1075,11 @@ func (m Model) renderWorktreesPanel(width, height int) string {
 	selectedRepo := m.state.GetSelectedRepo()
 	var header string
 	if selectedRepo != nil {
-		header = headerStyle.Render(fmt.Sprintf("Worktrees - %s", selectedRepo.Name))
+		title := fmt.Sprintf("Worktrees - %s", selectedRepo.Name)
+		if marked := len(m.state.MarkedWorktrees()); marked > 0 {
+			title += fmt.Sprintf(" (%d marked)", marked)
+		}
+		header = headerStyle.Render(title)
 	} else {
 		header = headerStyle.Render("Worktrees")
 	}
@@ -869,13 +1089,23 @@ func (m Model) renderWorktreesPanel(width, height int) string {
 	if len(m.state.Worktrees) == 0 {
 		if selectedRepo == nil {
 			items = append(items, infoStyle.Render("Select a repository first"))
+		} else if _, cached := m.state.Cache.Repo(selectedRepo.Name); !cached && m.state.Cache.RepoLoading(selectedRepo.Name) {
+			items = append(items, infoStyle.Render("Loading worktrees..."))
 		} else {
 			items = append(items, infoStyle.Render("No worktrees yet"))
 			items = append(items, infoStyle.Render("Press '+' to add one"))
 		}
 	} else {
 		for i, wt := range m.state.Worktrees {
-			itemText := fmt.Sprintf("%s [%s]", wt.Name, wt.Branch)
+			mark := " "
+			if m.state.Marked[wt.Path] {
+				mark = "●"
+			}
+			itemText := fmt.Sprintf("%s %s [%s]", mark, wt.Name, wt.Branch)
+			if wt.Locked {
+				itemText += " 🔒"
+			}
+			itemText = truncate(itemText, width-4)
 			if isActive && i == m.state.SelectedWTIndex {
 				items = append(items, selectedItemStyle.Render("> "+itemText))
 			} else {
@@ -887,21 +1117,10 @@ func (m Model) renderWorktreesPanel(width, height int) string {
 	// Add notes section if a worktree is selected
 	var notesSection string
 	if len(m.state.Worktrees) > 0 && m.state.SelectedWTIndex < len(m.state.Worktrees) {
-		selectedWT := m.state.Worktrees[m.state.SelectedWTIndex]
-
-		notesHeader := lipgloss.NewStyle().
-			Foreground(lipgloss.AdaptiveColor{Light: "#6B7280", Dark: "#9CA3AF"}).
-			Bold(true).
-			Render("\nNotes:")
+		notesHeader := sectionStyle.Render("\nNotes:")
 
 		// Show the notes in read-only mode
-		notes := ""
-		if selectedRepo != nil {
-			storedNotes, err := config.GetWorktreeNotes(selectedRepo.Name, selectedWT.Name)
-			if err == nil {
-				notes = storedNotes
-			}
-		}
+		notes := m.state.Notes
 
 		if notes != "" {
 			// Truncate notes if too long
@@ -912,78 +1131,77 @@ func (m Model) renderWorktreesPanel(width, height int) string {
 			}
 			// Replace newlines with spaces for compact display
 			displayNotes = strings.ReplaceAll(displayNotes, "\n", " ")
-			notesContent := lipgloss.NewStyle().
-				Foreground(lipgloss.AdaptiveColor{Light: "#4B5563", Dark: "#D1D5DB"}).
-				Italic(true).
-				Render("  " + displayNotes)
+			notesContent := notesStyle.Render("  " + displayNotes)
 			notesSection = notesHeader + "\n" + notesContent
 		} else {
-			emptyNotes := lipgloss.NewStyle().
-				Foreground(lipgloss.AdaptiveColor{Light: "#9CA3AF", Dark: "#6B7280"}).
-				Italic(true).
-				Render("  (no notes - press 'n' to add)")
+			emptyNotes := emptyNotesStyle.Render("  (no notes - press 'n' to add)")
 			notesSection = notesHeader + "\n" + emptyNotes
 		}
 	}
 
-	content := lipgloss.JoinVertical(lipgloss.Left, header, strings.Join(items, "\n"), notesSection)
-
-	return style.
-		Width(width).
-		Height(height).
-		Render(content)
-}
-
-// executeScript executes a script file with variable substitution
-// Returns error if script path is empty or execution fails
-func (m Model) executeScript(scriptPath string) error {
-	if scriptPath == "" {
-		return fmt.Errorf("no script configured")
+	// Add details section if enabled
+	var detailsSection string
+	if m.state.ShowDetails && m.state.Details != nil {
+		detailsSection = m.renderDetails(*m.state.Details)
 	}
 
-	// Expand ~ to home directory
-	if strings.HasPrefix(scriptPath, "~") {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return fmt.Errorf("failed to get home directory: %w", err)
+	// Add submodules section if the selected worktree has submodules
+	var submodulesSection string
+	if len(m.state.Submodules) > 0 {
+		submodulesHeader := sectionStyle.Render("\nSubmodules:")
+
+		var lines []string
+		for _, sub := range m.state.Submodules {
+			status := string(sub.Status)
+			if sub.Dirty {
+				status += ", dirty"
+			}
+			lines = append(lines, infoStyle.Render(fmt.Sprintf("  %s (%s)", sub.Path, status)))
 		}
-		scriptPath = filepath.Join(homeDir, scriptPath[1:])
+		submodulesSection = submodulesHeader + "\n" + strings.Join(lines, "\n")
 	}
 
-	// Read script file
-	scriptContent, err := os.ReadFile(scriptPath)
-	if err != nil {
-		return fmt.Errorf("failed to read script file %s: %w", scriptPath, err)
+	// The list gets the space the sections below leave
+	sections := lipgloss.JoinVertical(lipgloss.Left, notesSection, detailsSection, submodulesSection)
+	if len(m.state.Worktrees) > 0 {
+		listHeight := max(minListHeight, height-1-lipgloss.Height(sections))
+		items = m.scroll.worktrees.window(items, m.state.SelectedWTIndex, listHeight)
 	}
 
-	selectedWT := m.state.Worktrees[m.state.SelectedWTIndex]
-	selectedRepo := m.state.GetSelectedRepo()
+	content := lipgloss.JoinVertical(lipgloss.Left, header, strings.Join(items, "\n"), sections)
 
-	// Variable substitution
-	script := string(scriptContent)
-	script = strings.ReplaceAll(script, "${worktree_path}", selectedWT.Path)
-	script = strings.ReplaceAll(script, "${path}", selectedWT.Path)
-	script = strings.ReplaceAll(script, "${branch_name}", selectedWT.Branch)
-	script = strings.ReplaceAll(script, "${branch}", selectedWT.Branch)
-	if selectedRepo != nil {
-		script = strings.ReplaceAll(script, "${repo_name}", selectedRepo.Name)
-		script = strings.ReplaceAll(script, "${repo}", selectedRepo.Name)
-	}
+	return style.
+		Width(width).
+		Height(height).
+		Render(clipLines(content, height))
+}
 
-	// Execute the script
-	cmd := exec.Command("sh", "-c", script)
-	err = cmd.Start()
-	if err != nil {
-		return fmt.Errorf("failed to execute script: %w", err)
+// openWorktree runs the enter script for the selected worktree
+func (m Model) openWorktree() Model {
+	selectedWT := m.state.Worktrees[m.state.SelectedWTIndex]
+	if err := m.executeScript(m.state.Config.EnterScript, selectedWT); err != nil {
+		m.errorMsg = fmt.Sprintf("Enter key: %v. Set 'enter_script' to a script file path in config.toml", err)
+		m.successMsg = ""
+	} else {
+		m.successMsg = "Script executed"
+		m.errorMsg = ""
 	}
+	return m
+}
 
-	// Don't wait for the command - let it run independently
-	return nil
+// executeScript starts a script file for a worktree without waiting for it
+// Its output and exit status are recorded in the run log
+// Returns error if script path is empty or execution fails
+func (m Model) executeScript(scriptPath string, selectedWT state.Worktree) error {
+	return m.prepareScript(scriptPath, selectedWT).start(m.runLog)
 }
 
-func (m Model) renderHelp() string {
-	help := []string{
-		"Navigation: ↑↓ or j/k   Switch pane: tab or h/l   Add: +   Delete: -   Notes: n   Script: s   Yank: y   Open: Enter   Quit: q or ctrl+c",
+// prepareScript creates the command running a script file for a worktree of the selected repository
+func (m Model) prepareScript(scriptPath string, wt state.Worktree) preparedRun {
+	if scriptPath == "" {
+		return preparedRun{err: fmt.Errorf("no script configured")}
 	}
-	return helpStyle.Render(strings.Join(help, " • "))
+	repo := m.state.GetSelectedRepo()
+	cmd, err := scriptFileCommand(m.state.Config, scriptPath, repo, wt)
+	return preparedRun{cmd: cmd, run: newRun("Enter script", scriptPath, repo, wt), err: err}
 }
@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/michael-rose/workman/internal/state"
+)
+
+// doubleClickInterval is the maximum time between the two clicks of a double-click
+const doubleClickInterval = 400 * time.Millisecond
+
+// click is a left click on a list item, remembered to detect double-clicks
+type click struct {
+	at    time.Time
+	pane  state.Pane
+	index int
+}
+
+func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.dialogType != DialogNone {
+		return m.handleDialogMouse(msg)
+	}
+
+	// Dragging the divider resizes the panes; the split is saved when the button is released
+	layout := m.layout()
+	if m.dragging {
+		switch msg.Action {
+		case tea.MouseActionMotion:
+			m.state.Config.Split = m.splitAt(msg.X, msg.Y)
+		case tea.MouseActionRelease:
+			m.dragging = false
+			return m, saveLayout(m.state.Config)
+		}
+		return m, nil
+	}
+	if msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress && layout.onDivider(msg.X, msg.Y) {
+		m.dragging = true
+		m.state.Config.Split = clampSplit(m.state.Config.Split)
+		return m, nil
+	}
+
+	pane, row, ok := layout.paneAt(msg.X, msg.Y)
+	if !ok {
+		return m, nil
+	}
+
+	switch {
+	case msg.Button == tea.MouseButtonWheelUp || msg.Button == tea.MouseButtonWheelDown:
+		return m.scrollPane(pane, msg.Button == tea.MouseButtonWheelUp)
+
+	case msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress:
+		return m.clickPane(pane, row)
+	}
+
+	return m, nil
+}
+
+// scrollPane moves the selection of a pane with the mouse wheel and focuses the pane
+func (m Model) scrollPane(pane state.Pane, up bool) (tea.Model, tea.Cmd) {
+	m.state.ActivePane = pane
+	delta := 1
+	if up {
+		delta = -1
+	}
+	var cmd tea.Cmd
+	m, cmd = m.moveSelection(delta)
+	return m, cmd
+}
+
+// clickPane focuses a pane and selects the clicked item; a double-click on a worktree runs it like Enter
+func (m Model) clickPane(pane state.Pane, row int) (tea.Model, tea.Cmd) {
+	m.state.ActivePane = pane
+	var cmd tea.Cmd
+
+	if pane == state.ReposPane {
+		m.lastClick = click{}
+		if index, ok := m.scroll.repos.itemAt(row); ok && index < len(m.state.Config.Repositories) {
+			m, cmd = m.selectIndex(pane, index)
+		}
+		return m, cmd
+	}
+
+	index, ok := m.scroll.worktrees.itemAt(row)
+	if !ok || index >= len(m.state.Worktrees) {
+		m.lastClick = click{}
+		return m, nil
+	}
+
+	previous := m.lastClick
+	m.lastClick = click{at: time.Now(), pane: pane, index: index}
+	if previous.pane == pane && previous.index == index && time.Since(previous.at) <= doubleClickInterval {
+		m.lastClick = click{}
+		return m.launch()
+	}
+
+	m, cmd = m.selectIndex(pane, index)
+	return m, cmd
+}
+
+// handleDialogMouse scrolls dialogs and views with the mouse wheel
+func (m Model) handleDialogMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if msg.Button != tea.MouseButtonWheelUp && msg.Button != tea.MouseButtonWheelDown {
+		return m, nil
+	}
+
+	// Lists move their selection like the arrow keys
+	arrow := tea.KeyMsg{Type: tea.KeyDown}
+	if msg.Button == tea.MouseButtonWheelUp {
+		arrow = tea.KeyMsg{Type: tea.KeyUp}
+	}
+
+	var cmd tea.Cmd
+	switch m.dialogType {
+	case DialogDiff:
+		cmd = m.diffView.Update(msg)
+	case DialogOutput:
+		cmd = m.outputView.Update(msg)
+	case DialogHelp:
+		cmd = m.helpView.Update(msg)
+	case DialogStash:
+		if !m.stashView.Capturing() {
+			cmd = m.stashView.Update(arrow)
+		}
+	case DialogCleanup:
+		if !m.cleanupView.Confirming() {
+			cmd = m.cleanupView.Update(arrow)
+		}
+	case DialogOverview:
+		if !m.overviewView.Capturing() {
+			cmd = m.overviewView.Update(arrow)
+		}
+	case DialogPalette:
+		cmd = m.paletteView.Update(arrow)
+	case DialogActionPicker:
+		cmd = m.actionPicker.Update(arrow)
+	case DialogRuns:
+		cmd = m.runsView.Update(arrow)
+	}
+	return m, cmd
+}
@@ -0,0 +1,145 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxStreamedLines is the number of lines of a running command kept in the view;
+// the run log keeps all of them
+const maxStreamedLines = 1000
+
+// OutputView shows the output of a command in a scrollable overlay
+type OutputView struct {
+	title    string
+	lines    []string // complete lines of output
+	partial  string   // output after the last newline
+	dropped  int      // earlier lines of a running command that are no longer kept
+	failed   bool
+	running  bool       // output is still being appended; closing cancels the command
+	parent   DialogType // view shown again when closed
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+func NewOutputView(title, output string, failed bool, width, height int) OutputView {
+	v := OutputView{
+		title:    title,
+		failed:   failed,
+		viewport: viewport.New(0, 0),
+	}
+	v.SetSize(width, height)
+	if output = strings.TrimRight(output, "\n"); output != "" {
+		v.lines = strings.Split(output, "\n")
+	}
+	v.render()
+	return v
+}
+
+// Append adds output of a running command, following it while scrolled to the bottom
+// Only the last maxStreamedLines lines are kept, so that long output stays cheap to show
+func (v *OutputView) Append(text string) {
+	follow := v.viewport.AtBottom()
+
+	lines := strings.Split(v.partial+text, "\n")
+	for _, line := range lines[:len(lines)-1] {
+		v.lines = append(v.lines, overwritten(line))
+	}
+	v.partial = overwritten(lines[len(lines)-1])
+	if extra := len(v.lines) - maxStreamedLines; extra > 0 {
+		v.dropped += extra
+		v.lines = v.lines[extra:]
+	}
+
+	v.render()
+	if follow {
+		v.viewport.GotoBottom()
+	}
+}
+
+// overwritten returns what a terminal shows of a line that rewrites itself with carriage
+// returns, like a progress bar
+func overwritten(line string) string {
+	line = strings.TrimSuffix(line, "\r")
+	if i := strings.LastIndex(line, "\r"); i >= 0 {
+		return line[i+1:]
+	}
+	return line
+}
+
+// Finish marks the command as done, adding its outcome to the title
+func (v *OutputView) Finish(err error) {
+	v.running = false
+	v.failed = err != nil
+	if err != nil {
+		v.title += " · ✗ " + err.Error()
+	} else {
+		v.title += " · ✓ done"
+	}
+}
+
+// render sets the kept lines as content of the viewport
+func (v *OutputView) render() {
+	lines := v.lines
+	if v.dropped > 0 {
+		lines = append([]string{fmt.Sprintf("… %d earlier lines are in the run log", v.dropped)}, lines...)
+	}
+	if v.partial != "" {
+		lines = append(lines[:len(lines):len(lines)], v.partial)
+	}
+	v.viewport.SetContent(strings.Join(lines, "\n"))
+	v.fitHeight()
+}
+
+// fitHeight shrinks the viewport to short output
+func (v *OutputView) fitHeight() {
+	v.viewport.Height = max(3, min(v.viewport.TotalLineCount(), v.height-6))
+}
+
+// SetSize adapts the view to the terminal size
+func (v *OutputView) SetSize(width, height int) {
+	v.width = min(100, width-4)
+	v.height = height - 4
+
+	// Subtract padding as well as title and help lines
+	v.viewport.Width = max(10, v.width-4)
+	v.fitHeight()
+}
+
+func (v *OutputView) Update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	v.viewport, cmd = v.viewport.Update(msg)
+	return cmd
+}
+
+func (v *OutputView) View() string {
+	var b strings.Builder
+
+	b.WriteString(headerStyle.Render(v.title))
+	b.WriteString("\n\n")
+	b.WriteString(v.viewport.View())
+	b.WriteString("\n")
+	if v.running {
+		b.WriteString(helpLine(scrollHint, scriptCancelHint))
+	} else {
+		b.WriteString(helpLine(scrollHint, outputCloseHint))
+	}
+
+	frameColor := primaryColor
+	if v.failed {
+		frameColor = errorColor
+	}
+
+	dialogStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(frameColor).
+		Padding(1, 2).
+		Width(v.width)
+
+	return dialogStyle.Render(b.String())
+}
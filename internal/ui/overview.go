@@ -0,0 +1,409 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/michael-rose/workman/internal/config"
+	"github.com/michael-rose/workman/internal/git"
+	"github.com/michael-rose/workman/internal/state"
+)
+
+// overviewWorkers limits the number of worktrees inspected at the same time
+const overviewWorkers = 8
+
+// overviewRow is a worktree of any repository shown in the all worktrees view
+type overviewRow struct {
+	repoIndex    int
+	repoName     string
+	worktree     state.Worktree
+	details      state.WorktreeDetails
+	lastActivity time.Time
+	notes        string
+}
+
+// overviewLoadedMsg is sent when the worktrees of all repositories have been loaded
+type overviewLoadedMsg struct {
+	rows   []overviewRow
+	errors []string
+}
+
+// overviewSort is the column the all worktrees view is sorted by
+type overviewSort int
+
+const (
+	sortByRepo overviewSort = iota
+	sortByActivity
+	sortByBranch
+	sortByStatus
+)
+
+func (s overviewSort) String() string {
+	switch s {
+	case sortByActivity:
+		return "last activity"
+	case sortByBranch:
+		return "branch"
+	case sortByStatus:
+		return "status"
+	default:
+		return "repository"
+	}
+}
+
+// OverviewView lists the worktrees of all repositories in one table
+type OverviewView struct {
+	rows       []overviewRow
+	visible    []overviewRow // rows after filtering and sorting
+	errors     []string
+	loading    bool
+	sortBy     overviewSort
+	filter     textinput.Model
+	filterMode bool
+	selected   int
+	offset     int
+	width      int
+	height     int
+}
+
+func NewOverviewView(width, height int) OverviewView {
+	filter := textinput.New()
+	filter.Placeholder = "repo, branch, worktree or notes"
+	filter.Prompt = "/ "
+	filter.CharLimit = 100
+	filter.Width = 40
+
+	v := OverviewView{
+		loading: true,
+		filter:  filter,
+	}
+	v.SetSize(width, height)
+	return v
+}
+
+// SetSize adapts the view to the terminal size
+func (v *OverviewView) SetSize(width, height int) {
+	v.width = width - 4
+	v.height = height - 4
+}
+
+// Capturing reports whether the view consumes all keys (filter input)
+func (v *OverviewView) Capturing() bool {
+	return v.filterMode
+}
+
+// Selected returns the selected row, if any
+func (v *OverviewView) Selected() (overviewRow, bool) {
+	if v.selected >= len(v.visible) {
+		return overviewRow{}, false
+	}
+	return v.visible[v.selected], true
+}
+
+// SetRows replaces the loaded rows
+func (v *OverviewView) SetRows(rows []overviewRow, errors []string) {
+	v.rows = rows
+	v.errors = errors
+	v.loading = false
+	v.refresh()
+}
+
+// refresh applies filter and sort order and keeps the selection in range
+func (v *OverviewView) refresh() {
+	query := strings.ToLower(strings.TrimSpace(v.filter.Value()))
+	v.visible = v.visible[:0]
+	for _, row := range v.rows {
+		if query == "" || strings.Contains(strings.ToLower(row.searchText()), query) {
+			v.visible = append(v.visible, row)
+		}
+	}
+
+	sort.SliceStable(v.visible, func(i, j int) bool {
+		a, b := v.visible[i], v.visible[j]
+		switch v.sortBy {
+		case sortByActivity:
+			return a.lastActivity.After(b.lastActivity)
+		case sortByBranch:
+			return a.worktree.Branch < b.worktree.Branch
+		case sortByStatus:
+			return a.details.Changes > b.details.Changes
+		default:
+			if a.repoName != b.repoName {
+				return a.repoName < b.repoName
+			}
+			return a.worktree.Name < b.worktree.Name
+		}
+	})
+
+	if v.selected >= len(v.visible) {
+		v.selected = max(0, len(v.visible)-1)
+	}
+	v.scroll()
+}
+
+// listHeight is the number of rows that fit into the view
+func (v *OverviewView) listHeight() int {
+	return max(1, v.height-12)
+}
+
+// scroll moves the visible window so that the selection stays visible
+func (v *OverviewView) scroll() {
+	if v.selected < v.offset {
+		v.offset = v.selected
+	} else if v.selected >= v.offset+v.listHeight() {
+		v.offset = v.selected - v.listHeight() + 1
+	}
+}
+
+func (r overviewRow) searchText() string {
+	return strings.Join([]string{r.repoName, r.worktree.Name, r.worktree.Branch, r.notes}, " ")
+}
+
+// status renders uncommitted changes and upstream state of a row
+func (r overviewRow) status() string {
+	status := "clean"
+	if r.details.Changes > 0 {
+		status = fmt.Sprintf("%d changed", r.details.Changes)
+	}
+	if r.details.Upstream != "" {
+		status += " " + formatAheadBehind(r.details.Ahead, r.details.Behind)
+	}
+	return status
+}
+
+func (v *OverviewView) Update(msg tea.Msg) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+
+	if v.filterMode {
+		switch keyMsg.String() {
+		case "esc":
+			v.filter.SetValue("")
+			v.filterMode = false
+			v.filter.Blur()
+			v.refresh()
+			return nil
+		case "enter":
+			v.filterMode = false
+			v.filter.Blur()
+			return nil
+		}
+		var cmd tea.Cmd
+		v.filter, cmd = v.filter.Update(msg)
+		v.refresh()
+		return cmd
+	}
+
+	switch keyMsg.String() {
+	case "up", "k":
+		if v.selected > 0 {
+			v.selected--
+		}
+		v.scroll()
+	case "down", "j":
+		if v.selected < len(v.visible)-1 {
+			v.selected++
+		}
+		v.scroll()
+	case "s":
+		v.sortBy = (v.sortBy + 1) % 4
+		v.refresh()
+	case "/":
+		v.filterMode = true
+		return v.filter.Focus()
+	}
+
+	return nil
+}
+
+func (v *OverviewView) View() string {
+	var b strings.Builder
+
+	title := fmt.Sprintf("All worktrees (%d)", len(v.visible))
+	if v.loading {
+		title = "All worktrees - loading..."
+	}
+	b.WriteString(headerStyle.Render(title))
+	b.WriteString("\n")
+	b.WriteString(infoStyle.Render(fmt.Sprintf("Sorted by %s", v.sortBy)))
+	b.WriteString("\n")
+	if v.filterMode || v.filter.Value() != "" {
+		b.WriteString(v.filter.View())
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	// Column widths; the notes column takes the remaining space
+	repoWidth, nameWidth, branchWidth, statusWidth, activityWidth := 16, 20, 24, 18, 14
+	notesWidth := max(10, v.width-repoWidth-nameWidth-branchWidth-statusWidth-activityWidth-12)
+	row := func(cols ...string) string {
+		widths := []int{repoWidth, nameWidth, branchWidth, statusWidth, activityWidth, notesWidth}
+		for i, col := range cols {
+			cols[i] = fmt.Sprintf("%-*s", widths[i], truncate(col, widths[i]))
+		}
+		return strings.Join(cols, " ")
+	}
+	b.WriteString(diffMetaStyle.Render("  " + row("REPO", "WORKTREE", "BRANCH", "STATUS", "ACTIVITY", "NOTES")))
+	b.WriteString("\n")
+
+	listHeight := v.listHeight()
+	if !v.loading && len(v.visible) == 0 {
+		b.WriteString(infoStyle.Render("  No worktrees"))
+		b.WriteString("\n")
+	}
+	for i := v.offset; i < len(v.visible) && i < v.offset+listHeight; i++ {
+		r := v.visible[i]
+		activity := ""
+		if !r.lastActivity.IsZero() {
+			activity = formatAge(time.Since(r.lastActivity))
+		}
+		notes := strings.ReplaceAll(r.notes, "\n", " ")
+		text := row(r.repoName, r.worktree.Name, r.worktree.Branch, r.status(), activity, notes)
+		if i == v.selected {
+			b.WriteString(selectedItemStyle.Render("> " + text))
+		} else {
+			b.WriteString(itemStyle.Render("  " + text))
+		}
+		b.WriteString("\n")
+	}
+
+	for _, err := range v.errors {
+		b.WriteString(diffRemovedStyle.Render("  " + err))
+		b.WriteString("\n")
+	}
+
+	if v.filterMode {
+		b.WriteString(helpLine(filterApplyHint, filterClearHint))
+	} else {
+		b.WriteString(helpLine(navigateHint, overviewSortHint, overviewFilterHint, overviewReloadHint, overviewJumpHint, closeHint))
+	}
+
+	dialogStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(1, 2).
+		Width(v.width)
+
+	return dialogStyle.Render(b.String())
+}
+
+// truncate shortens s to width characters, ending with "…" if cut
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	if width <= 1 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// loadOverviewCmd loads the worktrees of all repositories concurrently
+func loadOverviewCmd(repos []config.Repository) tea.Cmd {
+	return func() tea.Msg {
+		var (
+			mu     sync.Mutex
+			wg     sync.WaitGroup
+			rows   []overviewRow
+			errors []string
+		)
+		sem := make(chan struct{}, overviewWorkers)
+
+		for i, repo := range repos {
+			wg.Add(1)
+			go func(repoIndex int, repo config.Repository) {
+				defer wg.Done()
+
+				sem <- struct{}{}
+				worktrees, err := git.ListWorktrees(repo.Path)
+				<-sem
+				if err != nil {
+					mu.Lock()
+					errors = append(errors, fmt.Sprintf("%s: %v", repo.Name, err))
+					mu.Unlock()
+					return
+				}
+
+				for _, wt := range worktrees {
+					wg.Add(1)
+					go func(wt state.Worktree) {
+						defer wg.Done()
+						sem <- struct{}{}
+						defer func() { <-sem }()
+
+						row := overviewRow{repoIndex: repoIndex, repoName: repo.Name, worktree: wt}
+						if details, err := git.GetWorktreeDetails(wt.Path, "", 1); err == nil {
+							row.details = details
+							row.lastActivity = details.LastModified
+							if details.Head.Date.After(row.lastActivity) {
+								row.lastActivity = details.Head.Date
+							}
+						}
+						row.notes, _ = config.GetWorktreeNotes(repo.Name, wt.Name)
+
+						mu.Lock()
+						rows = append(rows, row)
+						mu.Unlock()
+					}(wt)
+				}
+			}(i, repo)
+		}
+
+		wg.Wait()
+		sort.Strings(errors)
+		return overviewLoadedMsg{rows: rows, errors: errors}
+	}
+}
+
+// openOverview opens the all worktrees view and starts loading it
+func (m Model) openOverview() (tea.Model, tea.Cmd) {
+	m.overviewView = NewOverviewView(m.width, m.height)
+	m.dialogType = DialogOverview
+	m.errorMsg = ""
+	m.successMsg = ""
+	return m, loadOverviewCmd(m.state.Config.Repositories)
+}
+
+func (m Model) handleOverviewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if !m.overviewView.Capturing() {
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc", "q":
+			m.dialogType = DialogNone
+			return m, nil
+		case "r":
+			m.overviewView.loading = true
+			return m, loadOverviewCmd(m.state.Config.Repositories)
+		case "enter":
+			// Jump to the selected worktree in the main view
+			row, ok := m.overviewView.Selected()
+			if !ok || row.repoIndex >= len(m.state.Config.Repositories) {
+				return m, nil
+			}
+			m.state.SelectedRepoIndex = row.repoIndex
+			m, repoCmd := m.showRepo()
+			for i, wt := range m.state.Worktrees {
+				if wt.Path == row.worktree.Path {
+					m.state.SelectedWTIndex = i
+				}
+			}
+			m, wtCmd := m.showWorktree()
+			m.state.ActivePane = state.WorktreesPane
+			m.dialogType = DialogNone
+			return m, tea.Batch(repoCmd, wtCmd)
+		}
+	}
+
+	cmd := m.overviewView.Update(msg)
+	return m, cmd
+}
@@ -0,0 +1,217 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// paletteEntry is an action offered in the command palette
+type paletteEntry struct {
+	title string
+	keys  string // keys bound to the action, empty if it is only in the palette
+	run   func(Model) (tea.Model, tea.Cmd)
+}
+
+// PaletteView searches the actions available in the current context and runs the chosen one
+type PaletteView struct {
+	entries  []paletteEntry
+	matches  []paletteEntry
+	input    textinput.Model
+	selected int
+	offset   int
+	width    int
+	height   int
+}
+
+func NewPaletteView(entries []paletteEntry, width, height int) PaletteView {
+	input := textinput.New()
+	input.Placeholder = "type to search actions"
+	input.Prompt = ": "
+	input.CharLimit = 100
+	input.Focus()
+
+	v := PaletteView{
+		entries: entries,
+		input:   input,
+	}
+	v.SetSize(width, height)
+	v.filter()
+	return v
+}
+
+// SetSize adapts the view to the terminal size
+func (v *PaletteView) SetSize(width, height int) {
+	v.width = min(70, width-4)
+	v.height = height - 4
+	v.input.Width = max(10, v.width-8)
+}
+
+// visibleLines is the number of actions shown at once
+func (v *PaletteView) visibleLines() int {
+	return max(3, min(12, v.height-10))
+}
+
+// Selected returns the selected action, if any
+func (v *PaletteView) Selected() (paletteEntry, bool) {
+	if v.selected >= len(v.matches) {
+		return paletteEntry{}, false
+	}
+	return v.matches[v.selected], true
+}
+
+// filter matches the actions against the input; titles containing the input come before fuzzy matches
+func (v *PaletteView) filter() {
+	query := strings.ToLower(strings.TrimSpace(v.input.Value()))
+	var contained, fuzzy []paletteEntry
+	for _, entry := range v.entries {
+		switch {
+		case strings.Contains(strings.ToLower(entry.title), query):
+			contained = append(contained, entry)
+		case fuzzyMatch(strings.ReplaceAll(query, " ", ""), entry.title):
+			fuzzy = append(fuzzy, entry)
+		}
+	}
+	v.matches = append(contained, fuzzy...)
+	v.selected = 0
+	v.offset = 0
+}
+
+func (v *PaletteView) move(delta int) {
+	if len(v.matches) == 0 {
+		return
+	}
+	v.selected = (v.selected + delta + len(v.matches)) % len(v.matches)
+	lines := v.visibleLines()
+	if v.selected < v.offset {
+		v.offset = v.selected
+	} else if v.selected >= v.offset+lines {
+		v.offset = v.selected - lines + 1
+	}
+}
+
+func (v *PaletteView) Update(msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "up", "ctrl+p", "shift+tab":
+			v.move(-1)
+			return nil
+		case "down", "ctrl+n", "tab":
+			v.move(1)
+			return nil
+		}
+	}
+
+	previous := v.input.Value()
+	var cmd tea.Cmd
+	v.input, cmd = v.input.Update(msg)
+	if v.input.Value() != previous {
+		v.filter()
+	}
+	return cmd
+}
+
+func (v *PaletteView) View() string {
+	var b strings.Builder
+
+	b.WriteString(headerStyle.Render("Commands"))
+	b.WriteString("\n\n")
+	b.WriteString(v.input.View())
+	b.WriteString("\n\n")
+
+	if len(v.matches) == 0 {
+		b.WriteString(infoStyle.Render("  No matching actions"))
+		b.WriteString("\n")
+	}
+
+	keyStyle := lipgloss.NewStyle().Foreground(mutedColor)
+	end := min(len(v.matches), v.offset+v.visibleLines())
+	for i := v.offset; i < end; i++ {
+		entry := v.matches[i]
+		keys := keyStyle.Render(entry.keys)
+		title := truncate(entry.title, max(10, v.width-lipgloss.Width(keys)-10))
+		gap := strings.Repeat(" ", max(1, v.width-lipgloss.Width(title)-lipgloss.Width(keys)-10))
+		if i == v.selected {
+			b.WriteString(selectedItemStyle.Render("> " + title + gap))
+		} else {
+			b.WriteString(itemStyle.Render("  " + title + gap))
+		}
+		b.WriteString(keys)
+		b.WriteString("\n")
+	}
+	if hidden := len(v.matches) - end; hidden > 0 {
+		b.WriteString(infoStyle.Render(fmt.Sprintf("  ▼ %d more", hidden)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(helpLine(paletteSelectHint, paletteRunHint, cancelHint))
+
+	dialogStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(1, 2).
+		Width(v.width)
+
+	return dialogStyle.Render(b.String())
+}
+
+// paletteEntries lists the actions with a title that are available in the current context
+func (m Model) paletteEntries() []paletteEntry {
+	var entries []paletteEntry
+	for _, a := range actionRegistry() {
+		if a.title == "" || !a.available(m) {
+			continue
+		}
+		entry := paletteEntry{title: a.paletteTitle(m), run: a.run}
+		if b, ok := m.keys.lookup(a.name); ok && b.Enabled() {
+			entry.keys = b.Help().Key
+		}
+		entries = append(entries, entry)
+	}
+
+	// Named actions run on the selected or marked worktrees
+	if worktreeSelected(m) {
+		for _, a := range m.namedActions() {
+			entries = append(entries, paletteEntry{
+				title: "Run " + a.Name,
+				keys:  displayKey(normalizeKey(a.Key)),
+				run: func(m Model) (tea.Model, tea.Cmd) {
+					return m.runAction(a)
+				},
+			})
+		}
+	}
+	return entries
+}
+
+// openPalette opens the command palette
+func (m Model) openPalette() (tea.Model, tea.Cmd) {
+	m.paletteView = NewPaletteView(m.paletteEntries(), m.width, m.height)
+	m.dialogType = DialogPalette
+	m.errorMsg = ""
+	m.successMsg = ""
+	return m, textinput.Blink
+}
+
+func (m Model) handlePaletteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.dialogType = DialogNone
+		return m, nil
+	case "enter":
+		entry, ok := m.paletteView.Selected()
+		if !ok {
+			return m, nil
+		}
+		m.dialogType = DialogNone
+		return entry.run(m)
+	}
+
+	cmd := m.paletteView.Update(msg)
+	return m, cmd
+}
@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/michael-rose/workman/internal/git"
+)
+
+// pushItem describes a branch of a worktree to be pushed
+type pushItem struct {
+	worktreeName string
+	worktreePath string
+	branch       string
+	remote       string
+	commits      int
+}
+
+// ConfirmPushDialog lists the branches that will be pushed by a bulk push
+type ConfirmPushDialog struct {
+	items []pushItem
+}
+
+func NewConfirmPushDialog(items []pushItem) ConfirmPushDialog {
+	return ConfirmPushDialog{items: items}
+}
+
+func (d *ConfirmPushDialog) View() string {
+	var b strings.Builder
+
+	b.WriteString(headerStyle.Render("Push all worktrees"))
+	b.WriteString("\n\n")
+
+	b.WriteString(itemStyle.Render("The following branches will be pushed:"))
+	b.WriteString("\n")
+	for _, item := range d.items {
+		b.WriteString(infoStyle.Render(fmt.Sprintf("  • %s → %s (%d commit(s))", item.branch, item.remote, item.commits)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString(helpLine(pushConfirmHint, rejectHint))
+
+	dialogStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(1, 2).
+		Width(70)
+
+	return dialogStyle.Render(b.String())
+}
+
+// pushCmd pushes the given branches one after another in the background
+func pushCmd(title string, items []pushItem) tea.Cmd {
+	return func() tea.Msg {
+		var report bulkReport
+		for _, item := range items {
+			output, err := git.Push(item.worktreePath, item.remote, item.branch)
+			if err != nil {
+				report.failure(item.worktreeName, err)
+			} else {
+				report.success(item.worktreeName, fmt.Sprintf("→ %s/%s", item.remote, item.branch))
+			}
+			report.output(output)
+		}
+		return report.msg(title)
+	}
+}
+
+// resolvePushRemotes pushes each branch to the remote of its upstream, or to the default remote if it has none
+func resolvePushRemotes(items []pushItem) error {
+	for i := range items {
+		remote, err := git.PushRemote(items[i].worktreePath, items[i].branch)
+		if err != nil {
+			return err
+		}
+		items[i].remote = remote
+	}
+	return nil
+}
+
+// startPush pushes the branches of the marked worktrees, or the selected one, and sets their upstream
+func (m Model) startPush() (tea.Model, tea.Cmd) {
+	targets := m.state.TargetWorktrees()
+
+	var items []pushItem
+	for _, wt := range targets {
+		if wt.Branch == "detached HEAD" {
+			continue
+		}
+		items = append(items, pushItem{
+			worktreeName: wt.Name,
+			worktreePath: wt.Path,
+			branch:       wt.Branch,
+		})
+	}
+	if len(items) == 0 {
+		return m, showError("Cannot push a detached HEAD")
+	}
+	if err := resolvePushRemotes(items); err != nil {
+		return m, showError(fmt.Sprintf("Failed to push: %v", err))
+	}
+
+	m.errorMsg = ""
+	if len(items) == 1 {
+		m.successMsg = fmt.Sprintf("Pushing %s to %s...", items[0].branch, items[0].remote)
+		return m, pushCmd(fmt.Sprintf("Push %s", items[0].branch), items)
+	}
+	m.successMsg = fmt.Sprintf("Pushing %d branch(es)...", len(items))
+	return m, pushCmd("Push marked worktrees", items)
+}
+
+// confirmPushAll shows the branches of the selected repository that have unpushed commits
+func (m Model) confirmPushAll() (tea.Model, tea.Cmd) {
+
+	var items []pushItem
+	for _, wt := range m.state.Worktrees {
+		if wt.Branch == "detached HEAD" {
+			continue
+		}
+		commits, err := git.UnpushedCommits(wt.Path)
+		if err != nil || commits == 0 {
+			continue
+		}
+		items = append(items, pushItem{
+			worktreeName: wt.Name,
+			worktreePath: wt.Path,
+			branch:       wt.Branch,
+			commits:      commits,
+		})
+	}
+
+	if len(items) == 0 {
+		return m, showSuccess("All worktrees are pushed")
+	}
+	if err := resolvePushRemotes(items); err != nil {
+		return m, showError(fmt.Sprintf("Failed to push: %v", err))
+	}
+
+	m.confirmPushDialog = NewConfirmPushDialog(items)
+	m.dialogType = DialogConfirmPush
+	m.errorMsg = ""
+	m.successMsg = ""
+	return m, nil
+}
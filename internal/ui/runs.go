@@ -0,0 +1,285 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/michael-rose/workman/internal/config"
+	"github.com/michael-rose/workman/internal/runs"
+	"github.com/michael-rose/workman/internal/state"
+)
+
+// runFinishedMsg is sent when a script started in the background exits
+type runFinishedMsg struct {
+	run runs.Run
+}
+
+// newRunLog creates the log of script runs in the config directory
+func newRunLog() *runs.Log {
+	dir, err := config.RunsDir()
+	if err != nil {
+		dir = filepath.Join(os.TempDir(), "workman-runs")
+	}
+	return runs.New(dir, runs.DefaultKeep)
+}
+
+// newRun describes a run of a script or command on a worktree
+func newRun(name, command string, repo *config.Repository, wt state.Worktree) runs.Run {
+	run := runs.Run{Name: name, Command: command, Worktree: wt.Name}
+	if repo != nil {
+		run.Repo = repo.Name
+	}
+	return run
+}
+
+// waitForRun waits for the next script started in the background to exit
+func (m Model) waitForRun() tea.Cmd {
+	done := m.runLog.Done()
+	return func() tea.Msg {
+		return runFinishedMsg{run: <-done}
+	}
+}
+
+// handleRunFinished reports failed background runs and refreshes the runs view
+func (m Model) handleRunFinished(msg runFinishedMsg) (tea.Model, tea.Cmd) {
+	if m.dialogType == DialogRuns {
+		m.runsView.SetRuns(m.runLog.Runs())
+	}
+	if run := msg.run; run.Status() == runs.Failed {
+		m.successMsg = ""
+		m.errorMsg = fmt.Sprintf("%s failed in %s: %s (%s shows the log)", run.Name, run.Worktree, run.Error, m.keys.Runs.Help().Key)
+	}
+	return m, m.waitForRun()
+}
+
+// RunsView lists the recent runs of scripts and actions; the log of a run opens in an output view
+type RunsView struct {
+	runs     []runs.Run
+	selected int
+	offset   int
+	width    int
+	height   int
+}
+
+func NewRunsView(list []runs.Run, width, height int) RunsView {
+	v := RunsView{}
+	v.SetSize(width, height)
+	v.SetRuns(list)
+	return v
+}
+
+// SetSize adapts the view to the terminal size
+func (v *RunsView) SetSize(width, height int) {
+	v.width = width - 4
+	v.height = height - 4
+	v.scroll()
+}
+
+// SetRuns replaces the listed runs, keeping the selected run selected
+func (v *RunsView) SetRuns(list []runs.Run) {
+	var selectedID string
+	if run, ok := v.Selected(); ok {
+		selectedID = run.ID
+	}
+	v.runs = list
+	v.selected = 0
+	for i, run := range list {
+		if run.ID == selectedID {
+			v.selected = i
+		}
+	}
+	v.scroll()
+}
+
+// Selected returns the selected run, if any
+func (v *RunsView) Selected() (runs.Run, bool) {
+	if v.selected >= len(v.runs) {
+		return runs.Run{}, false
+	}
+	return v.runs[v.selected], true
+}
+
+// listHeight is the number of runs that fit into the view
+func (v *RunsView) listHeight() int {
+	return max(1, v.height-10)
+}
+
+// scroll moves the visible window so that the selection stays visible
+func (v *RunsView) scroll() {
+	if v.selected < v.offset {
+		v.offset = v.selected
+	} else if v.selected >= v.offset+v.listHeight() {
+		v.offset = v.selected - v.listHeight() + 1
+	}
+}
+
+func (v *RunsView) Update(msg tea.Msg) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+
+	switch keyMsg.String() {
+	case "up", "k":
+		if v.selected > 0 {
+			v.selected--
+		}
+	case "down", "j":
+		if v.selected < len(v.runs)-1 {
+			v.selected++
+		}
+	case "home", "g":
+		v.selected = 0
+	case "end", "G":
+		v.selected = max(0, len(v.runs)-1)
+	}
+	v.scroll()
+	return nil
+}
+
+// runStatus renders the status of a run with its exit code
+func runStatus(run runs.Run) string {
+	switch run.Status() {
+	case runs.Running:
+		return "● running"
+	case runs.Succeeded:
+		return "✓ ok"
+	case runs.Unknown:
+		return "? unknown"
+	}
+	if run.ExitCode >= 0 {
+		return fmt.Sprintf("✗ exit %d", run.ExitCode)
+	}
+	return "✗ failed"
+}
+
+// formatDuration renders a duration with a precision fitting its length
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+	return d.Round(time.Second).String()
+}
+
+func (v *RunsView) View() string {
+	var b strings.Builder
+
+	b.WriteString(headerStyle.Render(fmt.Sprintf("Script runs (%d)", len(v.runs))))
+	b.WriteString("\n")
+	b.WriteString(infoStyle.Render("Output of scripts and actions run by workman, newest first"))
+	b.WriteString("\n\n")
+
+	// Column widths; the command column takes the remaining space
+	statusWidth, nameWidth, worktreeWidth, startedWidth, durationWidth := 10, 20, 24, 14, 8
+	commandWidth := max(10, v.width-statusWidth-nameWidth-worktreeWidth-startedWidth-durationWidth-12)
+	row := func(cols ...string) string {
+		widths := []int{statusWidth, nameWidth, worktreeWidth, startedWidth, durationWidth, commandWidth}
+		for i, col := range cols {
+			cols[i] = fmt.Sprintf("%-*s", widths[i], truncate(col, widths[i]))
+		}
+		return strings.Join(cols, " ")
+	}
+	b.WriteString(diffMetaStyle.Render("  " + row("STATUS", "NAME", "WORKTREE", "STARTED", "TOOK", "COMMAND")))
+	b.WriteString("\n")
+
+	if len(v.runs) == 0 {
+		b.WriteString(infoStyle.Render("  No scripts run yet"))
+		b.WriteString("\n")
+	}
+	for i := v.offset; i < len(v.runs) && i < v.offset+v.listHeight(); i++ {
+		run := v.runs[i]
+		worktree := run.Worktree
+		if run.Repo != "" {
+			worktree = run.Repo + "/" + worktree
+		}
+		duration := ""
+		if run.Status() != runs.Unknown {
+			duration = formatDuration(run.Duration())
+		}
+		command := strings.Join(strings.Fields(run.Command), " ")
+		text := row(runStatus(run), run.Name, worktree, formatAge(time.Since(run.Started)), duration, command)
+		if i == v.selected {
+			b.WriteString(selectedItemStyle.Render("> " + text))
+		} else {
+			b.WriteString(itemStyle.Render("  " + text))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(helpLine(navigateHint, runsLogHint, overviewReloadHint, closeHint))
+
+	dialogStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(1, 2).
+		Width(v.width)
+
+	return dialogStyle.Render(b.String())
+}
+
+// openRuns opens the list of script runs
+func (m Model) openRuns() (tea.Model, tea.Cmd) {
+	m.runsView = NewRunsView(m.runLog.Runs(), m.width, m.height)
+	m.dialogType = DialogRuns
+	m.errorMsg = ""
+	m.successMsg = ""
+	return m, nil
+}
+
+// showRunLog opens the log of the selected run, returning to the runs view when closed
+func (m Model) showRunLog() (tea.Model, tea.Cmd) {
+	run, ok := m.runsView.Selected()
+	if !ok {
+		return m, nil
+	}
+
+	output := "The output was shown in the terminal."
+	if run.LogError != "" {
+		output = "No log was written: " + run.LogError
+	}
+	if run.LogPath != "" {
+		log, err := run.Output()
+		if err != nil {
+			return m, showError(fmt.Sprintf("Failed to read log: %v", err))
+		}
+		output = strings.TrimRight(log, "\n")
+		if output == "" {
+			output = "(no output)"
+		}
+	}
+	if run.Error != "" {
+		output += "\n\n" + run.Error
+	}
+
+	title := fmt.Sprintf("%s · %s · %s", run.Name, run.Worktree, runStatus(run))
+	m.outputView = NewOutputView(title, "$ "+run.Command+"\n\n"+output, run.Status() == runs.Failed, m.width, m.height)
+	m.outputView.parent = DialogRuns
+	m.dialogType = DialogOutput
+	return m, nil
+}
+
+func (m Model) handleRunsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q":
+		m.dialogType = DialogNone
+		return m, nil
+	case "r":
+		m.runsView.SetRuns(m.runLog.Runs())
+		return m, nil
+	case "enter", "l":
+		return m.showRunLog()
+	}
+
+	cmd := m.runsView.Update(msg)
+	return m, cmd
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/michael-rose/workman/internal/config"
+	"github.com/michael-rose/workman/internal/state"
+)
+
+func TestRunsView(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	cfg := &config.Config{
+		Repositories: []config.Repository{{Name: "repo", Path: dir}},
+		Actions:      []config.Action{{Name: "build", Key: "B", Command: "echo compiling; exit 2"}},
+	}
+	s := state.New(cfg)
+	m := NewModel(s, DefaultKeyMap())
+	s.ActivePane = state.WorktreesPane
+	s.Worktrees = []state.Worktree{{Name: "main", Branch: "main", Path: dir}}
+
+	// Failures of detached actions are reported when they exit
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'B'}})
+	m = model.(Model)
+	var msg tea.Msg
+	select {
+	case run := <-m.runLog.Done():
+		msg = runFinishedMsg{run: run}
+	case <-time.After(5 * time.Second):
+		t.Fatal("action did not finish")
+	}
+	model, _ = m.Update(msg)
+	m = model.(Model)
+	if !strings.Contains(m.errorMsg, "build failed in main: exit status 2") {
+		t.Errorf("error = %q", m.errorMsg)
+	}
+
+	// The runs view lists the run and shows its log
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	m = model.(Model)
+	if m.dialogType != DialogRuns || len(m.runsView.runs) != 1 {
+		t.Fatalf("dialog %v with %d runs", m.dialogType, len(m.runsView.runs))
+	}
+	if view := m.runsView.View(); !strings.Contains(view, "exit 2") || !strings.Contains(view, "repo/main") {
+		t.Errorf("runs view:\n%s", view)
+	}
+
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(Model)
+	if m.dialogType != DialogOutput || !strings.Contains(m.outputView.View(), "compiling") {
+		t.Fatalf("log not shown:\n%s", m.outputView.View())
+	}
+
+	// Closing the log returns to the list
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.(Model).dialogType != DialogRuns {
+		t.Errorf("dialog after closing the log = %v", model.(Model).dialogType)
+	}
+}
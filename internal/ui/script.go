@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/michael-rose/workman/internal/config"
+	"github.com/michael-rose/workman/internal/state"
+	"github.com/michael-rose/workman/internal/template"
+)
+
+// scriptEnv returns the environment of workman with the WORKMAN_* variables of a worktree
+func scriptEnv(repo *config.Repository, wt state.Worktree) []string {
+	var repoName, repoPath string
+	if repo != nil {
+		repoName, repoPath = repo.Name, repo.Path
+	}
+	return append(os.Environ(),
+		"WORKMAN_WORKTREE_PATH="+wt.Path,
+		"WORKMAN_WORKTREE_NAME="+wt.Name,
+		"WORKMAN_BRANCH="+wt.Branch,
+		"WORKMAN_REPO="+repoName,
+		"WORKMAN_REPO_PATH="+repoPath,
+	)
+}
+
+// substituteVariables replaces the legacy ${...} variables of a script with shell-quoted values
+func substituteVariables(script string, cfg *config.Config, repo *config.Repository, wt state.Worktree) string {
+	t := template.ParseScript(script)
+	return t.Execute(templateVars(t, cfg, repo, wt))
+}
+
+// expandHome expands a leading ~ to the home directory
+func expandHome(path string) (string, error) {
+	if !strings.HasPrefix(path, "~") {
+		return path, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, path[1:]), nil
+}
+
+// shebang returns the interpreter and its optional argument from the first line of a script
+// Files starting with a NUL byte in their first line are reported as binaries
+func shebang(path string) (interpreter []string, binary bool, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() { _ = file.Close() }()
+
+	line, err := bufio.NewReader(file).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, false, err
+	}
+	if !strings.HasPrefix(line, "#!") {
+		return nil, strings.ContainsRune(line, 0), nil
+	}
+	// Like the kernel, everything after the interpreter is passed as one argument
+	name, arg, _ := strings.Cut(strings.TrimSpace(line[2:]), " ")
+	if arg = strings.TrimSpace(arg); arg != "" {
+		return []string{name, arg}, false, nil
+	}
+	return []string{name}, false, nil
+}
+
+// scriptFileCommand creates the command running a script file for a worktree
+// The file is executed directly, honoring its shebang, with the WORKMAN_* variables in its
+// environment. With script_substitution its text is run by sh with the ${...} placeholders replaced
+func scriptFileCommand(cfg *config.Config, scriptPath string, repo *config.Repository, wt state.Worktree) (*exec.Cmd, error) {
+	scriptPath, err := expandHome(scriptPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.ScriptSubstitution {
+		content, err := os.ReadFile(scriptPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read script file %s: %w", scriptPath, err)
+		}
+		return shellCommand(cfg, string(content), repo, wt), nil
+	}
+
+	info, err := os.Stat(scriptPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read script file %s: %w", scriptPath, err)
+	}
+	interpreter, binary, err := shebang(scriptPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read script file %s: %w", scriptPath, err)
+	}
+
+	var cmd *exec.Cmd
+	executable := info.Mode()&0111 != 0
+	switch {
+	case executable && (interpreter != nil || binary):
+		cmd = exec.Command(scriptPath)
+	case interpreter != nil:
+		// Not executable: run the interpreter of the shebang ourselves
+		cmd = exec.Command(interpreter[0], append(interpreter[1:], scriptPath)...)
+	default:
+		// Scripts without shebang are shell scripts
+		cmd = exec.Command("sh", scriptPath)
+	}
+	cmd.Env = scriptEnv(repo, wt)
+	return cmd, nil
+}
+
+// shellCommand creates the command running inline shell code for a worktree
+func shellCommand(cfg *config.Config, script string, repo *config.Repository, wt state.Worktree) *exec.Cmd {
+	if cfg.ScriptSubstitution {
+		script = substituteVariables(script, cfg, repo, wt)
+	}
+	cmd := exec.Command("sh", "-c", script)
+	cmd.Env = scriptEnv(repo, wt)
+	return cmd
+}
@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/michael-rose/workman/internal/config"
+	"github.com/michael-rose/workman/internal/state"
+)
+
+func TestScriptFileCommand(t *testing.T) {
+	dir := t.TempDir()
+	repo := &config.Repository{Name: "repo", Path: "/repo"}
+	// A branch name that would run code if pasted into a script
+	wt := state.Worktree{Name: "evil", Branch: `x'"$(touch pwned)"`, Path: dir}
+
+	write := func(name, content string, perm os.FileMode) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), perm); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		legacy bool
+	}{
+		{name: "executable with shebang", path: write("exec.sh", "#!/bin/sh\nprintf '%s' \"$WORKMAN_BRANCH\"\n", 0755)},
+		{name: "shebang without exec bit", path: write("plain.sh", "#!/usr/bin/env sh\nprintf '%s' \"$WORKMAN_BRANCH\"\n", 0644)},
+		{name: "no shebang", path: write("noshebang.sh", "printf '%s' \"$WORKMAN_BRANCH\"\n", 0644)},
+		{name: "legacy substitution", path: write("legacy.sh", "printf '%s' ${branch_name}\n", 0644), legacy: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := scriptFileCommand(&config.Config{ScriptSubstitution: tt.legacy}, tt.path, repo, wt)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			cmd.Dir = dir
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("script failed: %v: %s", err, output)
+			}
+			if string(output) != wt.Branch {
+				t.Errorf("output = %q, want %q", output, wt.Branch)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "pwned")); err == nil {
+				t.Error("branch name was run as code")
+			}
+		})
+	}
+
+	if _, err := scriptFileCommand(&config.Config{}, filepath.Join(dir, "missing.sh"), repo, wt); err == nil {
+		t.Error("expected an error for a missing script")
+	}
+}
+
+func TestShellCommandEnvironment(t *testing.T) {
+	repo := &config.Repository{Name: "repo", Path: "/repo"}
+	wt := state.Worktree{Name: "feature", Branch: "feature/x", Path: "/work/repo-feature"}
+
+	output, err := shellCommand(&config.Config{}, "echo $WORKMAN_REPO $WORKMAN_REPO_PATH $WORKMAN_WORKTREE_NAME $WORKMAN_WORKTREE_PATH $WORKMAN_BRANCH ${branch_name}", repo, wt).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Placeholders are left to the shell without script_substitution
+	if got := strings.TrimSpace(string(output)); got != "repo /repo feature /work/repo-feature feature/x" {
+		t.Errorf("output = %q", got)
+	}
+}
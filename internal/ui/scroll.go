@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/michael-rose/workman/internal/state"
+)
+
+// minListHeight is the number of lines the worktree list keeps when the sections below it are long
+const minListHeight = 3
+
+// listScroll is the visible window of a list as last rendered
+// It is shared between copies of the model so that rendering can keep the selection visible
+type listScroll struct {
+	offset   int  // index of the first visible item
+	height   int  // number of lines available to the list
+	overflow bool // the list does not fit; the first and last line show scroll indicators
+}
+
+// scrollState holds the windows of the repository and worktree lists
+type scrollState struct {
+	repos     listScroll
+	worktrees listScroll
+}
+
+// window returns the lines of a list fitting into height lines, keeping the selected item visible
+// Lists that do not fit get an indicator of the hidden items above and below
+func (s *listScroll) window(items []string, selected, height int) []string {
+	s.height = height
+	s.overflow = len(items) > height
+	if !s.overflow {
+		s.offset = 0
+		return items
+	}
+
+	visible := max(1, height-2)
+	if selected < s.offset {
+		s.offset = selected
+	} else if selected >= s.offset+visible {
+		s.offset = selected - visible + 1
+	}
+	s.offset = max(0, min(s.offset, len(items)-visible))
+	end := min(len(items), s.offset+visible)
+
+	lines := []string{""}
+	if s.offset > 0 {
+		lines[0] = infoStyle.Render(fmt.Sprintf("  ▲ %d more", s.offset))
+	}
+	lines = append(lines, items[s.offset:end]...)
+	if end < len(items) {
+		lines = append(lines, infoStyle.Render(fmt.Sprintf("  ▼ %d more", len(items)-end)))
+	}
+	return lines
+}
+
+// itemAt returns the index of the item shown in a row of the list
+func (s *listScroll) itemAt(row int) (int, bool) {
+	if !s.overflow {
+		return row, row >= 0
+	}
+	// The first row holds the indicator of the items above
+	if row < 1 || row > max(1, s.height-2) {
+		return 0, false
+	}
+	return s.offset + row - 1, true
+}
+
+// pageSize is the number of items a page up/down moves
+func (s *listScroll) pageSize() int {
+	if s.overflow {
+		return max(1, s.height-2)
+	}
+	return max(1, s.height)
+}
+
+// selectIndex selects an item of a list, clamped to its bounds, and shows it
+func (m Model) selectIndex(pane state.Pane, index int) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	if pane == state.ReposPane {
+		count := len(m.state.Config.Repositories)
+		if count == 0 {
+			return m, nil
+		}
+		index = max(0, min(index, count-1))
+		if index != m.state.SelectedRepoIndex {
+			m.state.SelectedRepoIndex = index
+			m, cmd = m.showRepo()
+		}
+		return m, cmd
+	}
+
+	count := len(m.state.Worktrees)
+	if count == 0 {
+		return m, nil
+	}
+	index = max(0, min(index, count-1))
+	if index != m.state.SelectedWTIndex {
+		m.state.SelectedWTIndex = index
+		m, cmd = m.showWorktree()
+	}
+	return m, cmd
+}
+
+// moveSelection moves the selection of the active pane by delta items
+func (m Model) moveSelection(delta int) (Model, tea.Cmd) {
+	if m.state.ActivePane == state.ReposPane {
+		return m.selectIndex(state.ReposPane, m.state.SelectedRepoIndex+delta)
+	}
+	return m.selectIndex(state.WorktreesPane, m.state.SelectedWTIndex+delta)
+}
+
+// activeScroll returns the window of the list in the active pane
+func (m Model) activeScroll() *listScroll {
+	if m.state.ActivePane == state.ReposPane {
+		return &m.scroll.repos
+	}
+	return &m.scroll.worktrees
+}
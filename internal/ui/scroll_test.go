@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func testItems(n int) []string {
+	result := make([]string, n)
+	for i := range result {
+		result[i] = fmt.Sprintf("item %d", i)
+	}
+	return result
+}
+
+func TestListScrollWindow(t *testing.T) {
+	var s listScroll
+
+	// Lists that fit are shown completely
+	if lines := s.window(testItems(3), 2, 5); len(lines) != 3 || s.overflow {
+		t.Fatalf("short list: got %d lines, overflow %v", len(lines), s.overflow)
+	}
+
+	// Long lists keep the selection visible between the indicators
+	lines := s.window(testItems(20), 10, 7)
+	if len(lines) != 7 {
+		t.Fatalf("got %d lines, want 7", len(lines))
+	}
+	if s.offset != 6 {
+		t.Errorf("offset = %d, want 6", s.offset)
+	}
+	if !strings.Contains(lines[0], "▲ 6 more") || !strings.Contains(lines[6], "▼ 9 more") {
+		t.Errorf("missing indicators: %q / %q", lines[0], lines[6])
+	}
+	if lines[5] != "item 10" {
+		t.Errorf("last visible item = %q, want the selection", lines[5])
+	}
+
+	// Moving up within the window keeps the offset
+	s.window(testItems(20), 8, 7)
+	if s.offset != 6 {
+		t.Errorf("offset = %d, want 6", s.offset)
+	}
+
+	// The selection at the top scrolls back
+	lines = s.window(testItems(20), 0, 7)
+	if s.offset != 0 || lines[0] != "" || lines[1] != "item 0" {
+		t.Errorf("top: offset %d, lines %q", s.offset, lines[:2])
+	}
+}
+
+func TestListScrollItemAt(t *testing.T) {
+	var s listScroll
+	s.window(testItems(3), 0, 5)
+	if index, ok := s.itemAt(2); !ok || index != 2 {
+		t.Errorf("itemAt(2) = %d, %v; want 2", index, ok)
+	}
+
+	s.window(testItems(20), 10, 7)
+	tests := []struct {
+		row   int
+		index int
+		ok    bool
+	}{
+		{row: 0, ok: false}, // indicator
+		{row: 1, index: 6, ok: true},
+		{row: 5, index: 10, ok: true},
+		{row: 6, ok: false}, // indicator
+		{row: -1, ok: false},
+	}
+	for _, tt := range tests {
+		index, ok := s.itemAt(tt.row)
+		if ok != tt.ok || (ok && index != tt.index) {
+			t.Errorf("itemAt(%d) = %d, %v; want %d, %v", tt.row, index, ok, tt.index, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/michael-rose/workman/internal/git"
+	"github.com/michael-rose/workman/internal/runs"
+)
+
+// setupStep identifies a step that runs after a worktree has been created
+type setupStep int
+
+const (
+	setupSubmodules setupStep = iota
+	setupLFS
+	setupScript
+	setupDone
+)
+
+// worktreeSetup tracks the remaining setup of a newly created worktree
+type worktreeSetup struct {
+	repoName       string
+	repoPath       string
+	worktreePath   string
+	initSubmodules bool
+	fetchLFS       bool
+	script         string
+	timeout        time.Duration // of the post-create script, 0 for none
+	runLog         *runs.Log
+	step           setupStep
+
+	// Set while the post-create script runs
+	ctx    context.Context
+	output scriptOutput
+	done   chan struct{} // closed when the script has exited
+}
+
+// setupStepMsg is sent when a setup step has finished
+type setupStepMsg struct {
+	setup worktreeSetup
+	err   error
+}
+
+// nextStep advances to the next step that applies to the worktree
+func (s worktreeSetup) nextStep() worktreeSetup {
+	for ; s.step < setupDone; s.step++ {
+		switch s.step {
+		case setupSubmodules:
+			if s.initSubmodules && git.HasSubmodules(s.worktreePath) {
+				return s
+			}
+		case setupLFS:
+			if s.fetchLFS && git.UsesLFS(s.worktreePath) {
+				return s
+			}
+		case setupScript:
+			if s.script != "" {
+				return s
+			}
+		}
+	}
+	return s
+}
+
+// description returns the progress text shown while the step runs
+func (s worktreeSetup) description() string {
+	switch s.step {
+	case setupSubmodules:
+		return "updating submodules..."
+	case setupLFS:
+		return "fetching LFS objects..."
+	case setupScript:
+		return "running post-create script..."
+	}
+	return ""
+}
+
+// failure returns the error text shown when the step failed
+func (s worktreeSetup) failure() string {
+	switch s.step {
+	case setupSubmodules:
+		return "submodule update failed"
+	case setupLFS:
+		return "LFS fetch failed"
+	case setupScript:
+		return "setup failed in post-create script"
+	}
+	return "setup failed"
+}
+
+// run executes the current step in the background
+func (s worktreeSetup) run() tea.Cmd {
+	if s.step == setupScript {
+		return tea.Batch(s.runScript(), s.output.wait())
+	}
+	return func() tea.Msg {
+		var err error
+		switch s.step {
+		case setupSubmodules:
+			err = git.UpdateSubmodules(s.worktreePath)
+		case setupLFS:
+			err = git.PullLFS(s.worktreePath)
+		}
+		return setupStepMsg{setup: s, err: err}
+	}
+}
+
+// errScriptCancelled is the cause of cancelling a post-create script with esc or ctrl+c
+var errScriptCancelled = errors.New("cancelled")
+
+// scriptOutputMsg carries output of the running post-create script
+type scriptOutputMsg struct {
+	text   string
+	output scriptOutput
+}
+
+// scriptOutput delivers the output of the post-create script followed by its setupStepMsg
+type scriptOutput chan tea.Msg
+
+func (o scriptOutput) Write(p []byte) (int, error) {
+	o <- scriptOutputMsg{text: string(p), output: o}
+	return len(p), nil
+}
+
+// wait waits for the next output or the result of the script
+func (o scriptOutput) wait() tea.Cmd {
+	return func() tea.Msg {
+		return <-o
+	}
+}
+
+// runScript runs the post-create script, recording its output in the run log and streaming
+// it to the output channel; the result is sent after the last output
+func (s worktreeSetup) runScript() tea.Cmd {
+	return func() tea.Msg {
+		ctx := s.ctx
+		if s.timeout > 0 {
+			var stop context.CancelFunc
+			ctx, stop = context.WithTimeoutCause(ctx, s.timeout, fmt.Errorf("timed out after %s", s.timeout))
+			defer stop()
+		}
+
+		run := runs.Run{Name: "Post-create script", Repo: s.repoName, Worktree: filepath.Base(s.worktreePath), Command: "post-create script"}
+		cmd := git.PostCreateCommand(ctx, s.script, s.repoPath, s.worktreePath)
+		err := s.runLog.Stream(ctx, run, cmd, s.output)
+		close(s.done)
+		s.output <- setupStepMsg{setup: s, err: err}
+		return nil
+	}
+}
+
+// startScript starts the post-create script, showing its output unless another dialog is open
+// Then it can be cancelled with esc in the main view
+func (m Model) startScript(setup worktreeSetup) (Model, tea.Cmd) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	setup.ctx = ctx
+	setup.output = make(scriptOutput, 64)
+	setup.done = make(chan struct{})
+	m.cancelScript = cancel
+	m.scriptDone = setup.done
+
+	if m.dialogType == DialogNone {
+		title := fmt.Sprintf("Post-create script · %s", filepath.Base(setup.worktreePath))
+		m.outputView = NewOutputView(title, "", false, m.width, m.height)
+		m.outputView.running = true
+		m.dialogType = DialogOutput
+	} else {
+		m.successMsg = "Worktree created, running post-create script (esc in the main view cancels it)..."
+	}
+	return m, setup.run()
+}
+
+// scriptStopTimeout is how long quitting waits for the cancelled post-create script to exit
+const scriptStopTimeout = 2 * time.Second
+
+// stopScript cancels the running post-create script and waits until it has been killed
+func (m Model) stopScript() {
+	if m.cancelScript == nil {
+		return
+	}
+	m.cancelScript(errScriptCancelled)
+	select {
+	case <-m.scriptDone:
+	case <-time.After(scriptStopTimeout):
+	}
+}
+
+// handleScriptOutput shows output of the post-create script and waits for more
+func (m Model) handleScriptOutput(msg scriptOutputMsg) (tea.Model, tea.Cmd) {
+	if m.dialogType == DialogOutput && m.outputView.running {
+		m.outputView.Append(msg.text)
+	}
+	return m, msg.output.wait()
+}
+
+// cancelPostCreateScript kills the running post-create script and the processes it started
+func (m Model) cancelPostCreateScript() (tea.Model, tea.Cmd) {
+	if m.cancelScript != nil {
+		m.cancelScript(errScriptCancelled)
+	}
+	m.errorMsg = ""
+	m.successMsg = "Cancelling post-create script..."
+	return m, nil
+}
+
+// continueSetup starts the next pending setup step or reports completion
+func (m Model) continueSetup(setup worktreeSetup) (Model, tea.Cmd) {
+	setup = setup.nextStep()
+	if setup.step == setupDone {
+		m.errorMsg = ""
+		return m, showSuccess("Worktree created successfully")
+	}
+
+	m.errorMsg = ""
+	m.successMsg = "Worktree created, " + setup.description()
+	if setup.step == setupScript {
+		return m.startScript(setup)
+	}
+	return m, setup.run()
+}
+
+// handleSetupStep processes the result of a finished setup step
+func (m Model) handleSetupStep(msg setupStepMsg) (Model, tea.Cmd) {
+	m, loadCmd := m.refreshWorktree()
+	if msg.setup.step == setupScript {
+		if m.cancelScript != nil {
+			m.cancelScript(nil)
+			m.cancelScript = nil
+			m.scriptDone = nil
+		}
+		if m.dialogType == DialogOutput && m.outputView.running {
+			m.outputView.Finish(msg.err)
+		}
+	}
+	if msg.err != nil {
+		m.successMsg = ""
+		return m, tea.Batch(loadCmd, showError(fmt.Sprintf("Worktree created but %s: %v", msg.setup.failure(), msg.err)))
+	}
+
+	setup := msg.setup
+	setup.step++
+	m, cmd := m.continueSetup(setup)
+	return m, tea.Batch(loadCmd, cmd)
+}
+
+// pullLFSCmd fetches the LFS objects of a freshly cloned repository in the background
+func pullLFSCmd(repoName, repoPath string) tea.Cmd {
+	return func() tea.Msg {
+		if err := git.PullLFS(repoPath); err != nil {
+			return errorMsg{err: fmt.Sprintf("Repository '%s' added but LFS fetch failed: %v", repoName, err)}
+		}
+		return successMsg{msg: fmt.Sprintf("Repository '%s' added, LFS objects fetched", repoName)}
+	}
+}
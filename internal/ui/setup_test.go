@@ -0,0 +1,200 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/michael-rose/workman/internal/config"
+	"github.com/michael-rose/workman/internal/state"
+)
+
+// runCmds runs commands like the Bubble Tea runtime, delivering their messages to msgs
+func runCmds(cmd tea.Cmd, msgs chan<- tea.Msg) {
+	if cmd == nil {
+		return
+	}
+	go func() {
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for _, c := range batch {
+				runCmds(c, msgs)
+			}
+		} else if msg != nil {
+			msgs <- msg
+		}
+	}()
+}
+
+// runPostCreateScript runs a post-create script in the model until the setup failed,
+// calling onOutput with each output shown
+func runPostCreateScript(t *testing.T, script string, timeout time.Duration, onOutput func(Model) Model) Model {
+	t.Helper()
+	dir := t.TempDir()
+	m := NewModel(state.New(&config.Config{}), DefaultKeyMap())
+	m.width, m.height = 100, 40
+
+	msgs := make(chan tea.Msg, 16)
+	m, cmd := m.continueSetup(worktreeSetup{repoName: "repo", repoPath: dir, worktreePath: dir, script: script, timeout: timeout, runLog: m.runLog})
+	if m.dialogType != DialogOutput || !m.outputView.running {
+		t.Fatalf("script output not shown, dialog %v", m.dialogType)
+	}
+	runCmds(cmd, msgs)
+
+	for m.errorMsg == "" {
+		select {
+		case msg := <-msgs:
+			model, cmd := m.Update(msg)
+			m = model.(Model)
+			if _, ok := msg.(scriptOutputMsg); ok && onOutput != nil {
+				m = onOutput(m)
+			}
+			runCmds(cmd, msgs)
+		case <-time.After(10 * time.Second):
+			t.Fatal("script was not stopped")
+		}
+	}
+	return m
+}
+
+func TestPostCreateScriptCancel(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	m := runPostCreateScript(t, "echo started; sleep 30", 0, func(m Model) Model {
+		if !strings.Contains(m.outputView.View(), "started") {
+			t.Errorf("output not shown live:\n%s", m.outputView.View())
+		}
+		model, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		m = model.(Model)
+		if m.dialogType != DialogOutput {
+			t.Errorf("esc closed the output of the running script")
+		}
+		return m
+	})
+
+	if m.errorMsg != "Worktree created but setup failed in post-create script: cancelled" {
+		t.Errorf("error = %q", m.errorMsg)
+	}
+	if m.outputView.running || !m.outputView.failed || !strings.Contains(m.outputView.title, "✗ cancelled") {
+		t.Errorf("output view not finished: %q", m.outputView.title)
+	}
+	if m.cancelScript != nil {
+		t.Error("cancel function kept after the script stopped")
+	}
+
+	// Once finished, the output can be closed
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.(Model).dialogType != DialogNone {
+		t.Errorf("dialog after closing = %v", model.(Model).dialogType)
+	}
+}
+
+func TestPostCreateScriptCancelFromMainView(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	m := NewModel(state.New(&config.Config{}), DefaultKeyMap())
+
+	// Another dialog is open when the script starts, so its output is not shown
+	m.dialogType = DialogHelp
+	msgs := make(chan tea.Msg, 16)
+	m, cmd := m.continueSetup(worktreeSetup{repoName: "repo", repoPath: dir, worktreePath: dir, script: "sleep 30", runLog: m.runLog})
+	if m.dialogType != DialogHelp {
+		t.Fatalf("dialog = %v", m.dialogType)
+	}
+	runCmds(cmd, msgs)
+
+	m.dialogType = DialogNone
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = model.(Model)
+	for m.errorMsg == "" {
+		select {
+		case msg := <-msgs:
+			model, cmd := m.Update(msg)
+			m = model.(Model)
+			runCmds(cmd, msgs)
+		case <-time.After(10 * time.Second):
+			t.Fatal("script was not stopped")
+		}
+	}
+	if m.errorMsg != "Worktree created but setup failed in post-create script: cancelled" {
+		t.Errorf("error = %q", m.errorMsg)
+	}
+}
+
+func TestCloseStopsPostCreateScript(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	m := NewModel(state.New(&config.Config{}), DefaultKeyMap())
+
+	m, cmd := m.continueSetup(worktreeSetup{repoName: "repo", repoPath: dir, worktreePath: dir, script: "sleep 30", runLog: m.runLog})
+	runCmds(cmd, make(chan tea.Msg, 16))
+
+	start := time.Now()
+	m.Close()
+	if took := time.Since(start); took >= scriptStopTimeout {
+		t.Errorf("Close waited %v for the script", took)
+	}
+	select {
+	case <-m.scriptDone:
+	default:
+		t.Error("Expected the script to have exited after Close")
+	}
+}
+
+func TestPostCreateScriptTimeout(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	m := runPostCreateScript(t, "sleep 30", 200*time.Millisecond, nil)
+	if !strings.Contains(m.errorMsg, "setup failed in post-create script: timed out after 200ms") {
+		t.Errorf("error = %q", m.errorMsg)
+	}
+	if runs := m.runLog.Runs(); len(runs) != 1 || runs[0].Error != "timed out after 200ms" {
+		t.Errorf("runs = %+v", runs)
+	}
+}
+
+func TestOutputViewAppend(t *testing.T) {
+	v := NewOutputView("Post-create script", "", false, 100, 40)
+	v.running = true
+
+	// Lines split across chunks are joined, progress rewritten with \r shows its last state
+	v.Append("inst")
+	v.Append("alling\nprogress 10%\rprogress 50%")
+	v.Append("\rprogress 100%\r\n")
+	if len(v.lines) != 2 || v.lines[0] != "installing" || v.lines[1] != "progress 100%" || v.partial != "" {
+		t.Errorf("lines = %q, partial = %q", v.lines, v.partial)
+	}
+
+	// Only the last lines of long output are kept
+	for i := 0; i < 3*maxStreamedLines; i++ {
+		v.Append(fmt.Sprintf("line %d\n", i))
+	}
+	v.Append("last")
+	if len(v.lines) != maxStreamedLines || v.dropped != 2*maxStreamedLines+2 {
+		t.Errorf("kept %d lines, dropped %d", len(v.lines), v.dropped)
+	}
+	if view := v.View(); !strings.Contains(view, "last") || !strings.Contains(view, fmt.Sprintf("line %d", 3*maxStreamedLines-1)) {
+		t.Errorf("view does not follow the output:\n%s", view)
+	}
+	v.viewport.GotoTop()
+	if view := v.viewport.View(); !strings.HasPrefix(view, "… 2002 earlier lines are in the run log") {
+		t.Errorf("dropped lines not mentioned:\n%s", view)
+	}
+}
+
+func TestBackgroundErrorReplacesProgress(t *testing.T) {
+	m := NewModel(state.New(&config.Config{}), DefaultKeyMap())
+	m.width, m.height = 100, 40
+	m.successMsg = "Repository 'repo' added, fetching LFS objects..."
+
+	model, _ := m.Update(errorMsg{err: "Repository 'repo' added but LFS fetch failed"})
+	m = model.(Model)
+	if m.successMsg != "" {
+		t.Errorf("Expected progress message to be cleared, got %q", m.successMsg)
+	}
+	if view := m.View(); !strings.Contains(view, "LFS fetch failed") {
+		t.Error("Expected the error to be shown")
+	}
+}
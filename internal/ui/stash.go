@@ -0,0 +1,204 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/michael-rose/workman/internal/git"
+)
+
+// showStashDiffMsg requests the diff of a stash to be shown
+type showStashDiffMsg struct {
+	stash git.Stash
+}
+
+// StashView lists the stashes of a repository; stash, apply and pop act on the selected worktree
+type StashView struct {
+	worktreeName string
+	worktreePath string
+	branch       string
+	stashes      []git.Stash
+	selected     int
+	input        textinput.Model
+	inputActive  bool
+	confirmDrop  bool
+}
+
+func NewStashView(worktreeName, worktreePath, branch string) (StashView, error) {
+	input := textinput.New()
+	input.Placeholder = "optional message"
+	input.CharLimit = 200
+	input.Width = 50
+
+	v := StashView{
+		worktreeName: worktreeName,
+		worktreePath: worktreePath,
+		branch:       branch,
+		input:        input,
+	}
+	if err := v.load(); err != nil {
+		return v, err
+	}
+	return v, nil
+}
+
+// load refreshes the stash list and keeps the selection in range
+func (v *StashView) load() error {
+	stashes, err := git.ListStashes(v.worktreePath)
+	if err != nil {
+		return err
+	}
+	v.stashes = stashes
+	if v.selected >= len(v.stashes) {
+		v.selected = max(0, len(v.stashes)-1)
+	}
+	return nil
+}
+
+// Capturing reports whether the view consumes all keys (message input or drop confirmation)
+func (v *StashView) Capturing() bool {
+	return v.inputActive || v.confirmDrop
+}
+
+func (v *StashView) Update(msg tea.Msg) tea.Cmd {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+
+	if v.inputActive {
+		switch keyMsg.String() {
+		case "esc":
+			v.inputActive = false
+			v.input.Blur()
+			return nil
+		case "enter":
+			v.inputActive = false
+			v.input.Blur()
+			if err := git.StashPush(v.worktreePath, v.input.Value()); err != nil {
+				return showError(fmt.Sprintf("Failed to stash changes: %v", err))
+			}
+			return v.reload("Changes stashed")
+		}
+		var cmd tea.Cmd
+		v.input, cmd = v.input.Update(msg)
+		return cmd
+	}
+
+	if v.confirmDrop {
+		v.confirmDrop = false
+		if keyMsg.String() != "y" || len(v.stashes) == 0 {
+			return nil
+		}
+		stash := v.stashes[v.selected]
+		if err := git.StashDrop(v.worktreePath, stash.Ref); err != nil {
+			return showError(fmt.Sprintf("Failed to drop stash: %v", err))
+		}
+		return v.reload(fmt.Sprintf("Dropped %s", stash.Ref))
+	}
+
+	switch keyMsg.String() {
+	case "up", "k":
+		if v.selected > 0 {
+			v.selected--
+		}
+	case "down", "j":
+		if v.selected < len(v.stashes)-1 {
+			v.selected++
+		}
+	case "s":
+		v.input.SetValue("")
+		v.inputActive = true
+		return v.input.Focus()
+	}
+
+	if len(v.stashes) == 0 {
+		return nil
+	}
+	stash := v.stashes[v.selected]
+
+	switch keyMsg.String() {
+	case "a":
+		if err := git.StashApply(v.worktreePath, stash.Ref); err != nil {
+			return showError(fmt.Sprintf("Failed to apply stash: %v", err))
+		}
+		return v.reload(fmt.Sprintf("Applied %s to %s", stash.Ref, v.worktreeName))
+	case "p":
+		if err := git.StashPop(v.worktreePath, stash.Ref); err != nil {
+			return showError(fmt.Sprintf("Failed to pop stash: %v", err))
+		}
+		return v.reload(fmt.Sprintf("Popped %s into %s", stash.Ref, v.worktreeName))
+	case "x":
+		v.confirmDrop = true
+	case "enter", "d":
+		return func() tea.Msg {
+			return showStashDiffMsg{stash: stash}
+		}
+	}
+
+	return nil
+}
+
+// reload refreshes the list after an action and reports the result
+func (v *StashView) reload(success string) tea.Cmd {
+	if err := v.load(); err != nil {
+		return showError(fmt.Sprintf("Failed to list stashes: %v", err))
+	}
+	return showSuccess(success)
+}
+
+func (v *StashView) View() string {
+	var b strings.Builder
+
+	b.WriteString(headerStyle.Render(fmt.Sprintf("Stashes - %s [%s]", v.worktreeName, v.branch)))
+	b.WriteString("\n")
+	b.WriteString(infoStyle.Render("Stashes are shared by all worktrees of the repository. ● marks stashes of this branch."))
+	b.WriteString("\n\n")
+
+	if len(v.stashes) == 0 {
+		b.WriteString(infoStyle.Render("  No stashes"))
+		b.WriteString("\n")
+	}
+	for i, stash := range v.stashes {
+		marker := " "
+		if stash.Branch == v.branch {
+			marker = "●"
+		}
+		text := fmt.Sprintf("%s %s  %s  %s (%s)", marker, stash.Ref, stash.Branch, stash.Message, formatAge(time.Since(stash.Date)))
+		if i == v.selected {
+			b.WriteString(selectedItemStyle.Render("> " + text))
+		} else {
+			b.WriteString(itemStyle.Render("  " + text))
+		}
+		b.WriteString("\n")
+	}
+
+	switch {
+	case v.inputActive:
+		b.WriteString("\n")
+		b.WriteString(itemStyle.Render("Stash message:"))
+		b.WriteString("\n")
+		b.WriteString(v.input.View())
+		b.WriteString("\n")
+		b.WriteString(helpLine(stashSubmitHint, cancelHint))
+	case v.confirmDrop:
+		b.WriteString("\n")
+		b.WriteString(itemStyle.Render(fmt.Sprintf("Drop %s? This cannot be undone.", v.stashes[v.selected].Ref)))
+		b.WriteString("\n")
+		b.WriteString(helpLine(confirmHint, anyCancelHint))
+	default:
+		b.WriteString(helpLine(stashHint, stashApplyHint, stashPopHint, stashDropHint, stashDiffHint, closeHint))
+	}
+
+	dialogStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(1, 2).
+		Width(90)
+
+	return dialogStyle.Render(b.String())
+}
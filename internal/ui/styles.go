@@ -2,62 +2,147 @@ package ui
 
 import "github.com/charmbracelet/lipgloss"
 
+// Colors and styles of the active theme, set by ApplyTheme
 var (
-	// Adaptive colors that work on both light and dark backgrounds
-	primaryColor = lipgloss.AdaptiveColor{
-		Light: "#7C3AED", // Purple
-		Dark:  "#A78BFA", // Lighter purple for dark backgrounds
-	}
+	primaryColor   lipgloss.TerminalColor
+	mutedColor     lipgloss.TerminalColor
+	borderColor    lipgloss.TerminalColor
+	selectedBg     lipgloss.TerminalColor
+	successColor   lipgloss.TerminalColor
+	errorColor     lipgloss.TerminalColor
+	highlightColor lipgloss.TerminalColor
+	backdropColor  lipgloss.TerminalColor
 
-	mutedColor = lipgloss.AdaptiveColor{
-		Light: "#6B7280", // Medium gray
-		Dark:  "#9CA3AF", // Lighter gray
-	}
+	panelStyle        lipgloss.Style
+	activePanelStyle  lipgloss.Style
+	headerStyle       lipgloss.Style
+	itemStyle         lipgloss.Style
+	selectedItemStyle lipgloss.Style
+	infoStyle         lipgloss.Style
+	helpStyle         lipgloss.Style
+	helpTitleStyle    lipgloss.Style
+	sectionStyle      lipgloss.Style // section headers like "Notes:" below the worktrees
+	notesStyle        lipgloss.Style
+	emptyNotesStyle   lipgloss.Style
+	successStyle      lipgloss.Style
+	errorStyle        lipgloss.Style
+	successBanner     lipgloss.Style // success message above the panels
+	errorBanner       lipgloss.Style // error message above the panels
 
-	borderColor = lipgloss.AdaptiveColor{
-		Light: "#D1D5DB", // Light gray
-		Dark:  "#4B5563", // Dark gray
-	}
+	// Diff styles
+	diffAddedStyle   lipgloss.Style
+	diffRemovedStyle lipgloss.Style
+	diffHunkStyle    lipgloss.Style
+	diffMetaStyle    lipgloss.Style
+)
 
-	selectedBg = lipgloss.AdaptiveColor{
-		Light: "#EDE9FE", // Very light purple
-		Dark:  "#1F2937", // Dark gray
-	}
+func init() {
+	ApplyTheme(defaultTheme())
+}
+
+// ApplyTheme sets the colors and styles of the user interface
+func ApplyTheme(t Theme) {
+	primaryColor = t.Primary
+	mutedColor = t.Muted
+	borderColor = t.Border
+	selectedBg = t.SelectedBg
+	successColor = t.Success
+	errorColor = t.Error
+	highlightColor = t.Highlight
+	backdropColor = t.Backdrop
 
 	// Panel styles
 	panelStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(borderColor).
-			Padding(0, 1)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Padding(0, 1)
 
 	activePanelStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(primaryColor).
-				Padding(0, 1)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(0, 1)
 
 	// Header styles
 	headerStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(primaryColor).
-			Padding(0, 1)
+		Bold(true).
+		Foreground(primaryColor).
+		Padding(0, 1)
 
 	// Item styles - use terminal default foreground color
 	itemStyle = lipgloss.NewStyle().
-			Padding(0, 1)
+		Padding(0, 1)
 
 	selectedItemStyle = lipgloss.NewStyle().
-				Foreground(primaryColor).
-				Background(selectedBg).
-				Bold(true).
-				Padding(0, 1)
+		Foreground(primaryColor).
+		Background(selectedBg).
+		Bold(true).
+		Padding(0, 1)
 
 	// Info styles
 	infoStyle = lipgloss.NewStyle().
-			Foreground(mutedColor).
-			Italic(true)
+		Foreground(mutedColor).
+		Italic(true)
 
 	// Help text style
 	helpStyle = lipgloss.NewStyle().
-			Foreground(mutedColor).
-			Padding(1, 0, 0, 2)
-)
+		Foreground(mutedColor).
+		Padding(1, 0, 0, 2)
+
+	helpTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryColor)
+
+	// Worktree panel sections
+	sectionStyle = lipgloss.NewStyle().
+		Foreground(mutedColor).
+		Bold(true)
+
+	notesStyle = lipgloss.NewStyle().
+		Foreground(t.Text).
+		Italic(true)
+
+	emptyNotesStyle = lipgloss.NewStyle().
+		Foreground(t.Faint).
+		Italic(true)
+
+	// Feedback styles
+	successStyle = lipgloss.NewStyle().
+		Foreground(successColor).
+		Bold(true).
+		Padding(0, 2)
+
+	errorStyle = lipgloss.NewStyle().
+		Foreground(errorColor).
+		Bold(true).
+		Padding(0, 2)
+
+	successBanner = successStyle.
+		Background(t.SuccessBg)
+
+	errorBanner = errorStyle.
+		Background(t.ErrorBg)
+
+	// Diff styles
+	diffAddedStyle = lipgloss.NewStyle().
+		Foreground(successColor)
+
+	diffRemovedStyle = lipgloss.NewStyle().
+		Foreground(errorColor)
+
+	diffHunkStyle = lipgloss.NewStyle().
+		Foreground(t.Info)
+
+	diffMetaStyle = lipgloss.NewStyle().
+		Foreground(mutedColor).
+		Bold(true)
+
+	// Without colors the selection is reversed and the active pane gets a thick border
+	if t.Monochrome {
+		selectedItemStyle = selectedItemStyle.Reverse(true)
+		activePanelStyle = activePanelStyle.Border(lipgloss.ThickBorder())
+		successBanner = successBanner.Reverse(true)
+		errorBanner = errorBanner.Reverse(true)
+	}
+
+	helpModel = newHelpModel()
+}
@@ -0,0 +1,270 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/michael-rose/workman/internal/config"
+	"github.com/michael-rose/workman/internal/git"
+	"github.com/michael-rose/workman/internal/state"
+)
+
+// syncResultMsg is sent when syncing a single worktree with its base branch has finished
+type syncResultMsg struct {
+	worktreeName string
+	worktreePath string
+	baseBranch   string
+	err          error
+}
+
+// bulkSyncResultMsg is sent when syncing all clean worktrees of a repository has finished
+type bulkSyncResultMsg struct {
+	updated []string
+	skipped []string
+	failed  []string
+}
+
+// SyncConflictDialog shows the conflicted files of a stopped rebase or merge
+type SyncConflictDialog struct {
+	worktreeName string
+	worktreePath string
+	operation    string
+	files        []string
+}
+
+func NewSyncConflictDialog(worktreeName, worktreePath, operation string, files []string) SyncConflictDialog {
+	return SyncConflictDialog{
+		worktreeName: worktreeName,
+		worktreePath: worktreePath,
+		operation:    operation,
+		files:        files,
+	}
+}
+
+func (d *SyncConflictDialog) View() string {
+	var b strings.Builder
+
+	b.WriteString(headerStyle.Render(fmt.Sprintf("⚠ %s conflicts - %s", strings.ToUpper(d.operation[:1])+d.operation[1:], d.worktreeName)))
+	b.WriteString("\n\n")
+
+	if len(d.files) == 0 {
+		b.WriteString(itemStyle.Render("All conflicts resolved."))
+		b.WriteString("\n")
+	} else {
+		b.WriteString(itemStyle.Render("Conflicted files:"))
+		b.WriteString("\n")
+		for _, file := range d.files {
+			b.WriteString(infoStyle.Render("  • " + file))
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString("\n")
+
+	hint := infoStyle.Render(fmt.Sprintf("Resolve and stage the files in %s, then continue.", d.worktreePath))
+	b.WriteString(hint)
+	b.WriteString("\n\n")
+
+	b.WriteString(helpLine(syncContinueHint, syncAbortHint, syncCloseHint))
+
+	dialogStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(errorColor).
+		Padding(1, 2).
+		Width(70)
+
+	return dialogStyle.Render(b.String())
+}
+
+// syncStrategy returns the configured sync strategy of a repository
+func syncStrategy(repo *config.Repository) string {
+	if repo.SyncStrategy == git.SyncMerge {
+		return git.SyncMerge
+	}
+	return git.SyncRebase
+}
+
+// startSync fetches and rebases/merges the selected worktree onto its base branch
+func (m Model) startSync() (tea.Model, tea.Cmd) {
+	repo := m.state.GetSelectedRepo()
+	selectedWT := m.state.Worktrees[m.state.SelectedWTIndex]
+
+	// Offer to continue or abort an operation that is already in progress
+	if operation := git.SyncInProgress(selectedWT.Path); operation != "" {
+		files, _ := git.ConflictedFiles(selectedWT.Path)
+		m.syncConflictDialog = NewSyncConflictDialog(selectedWT.Name, selectedWT.Path, operation, files)
+		m.dialogType = DialogSyncConflict
+		m.errorMsg = ""
+		m.successMsg = ""
+		return m, nil
+	}
+
+	if selectedWT.Branch == "detached HEAD" {
+		return m, showError("Cannot sync a detached HEAD")
+	}
+
+	clean, err := git.IsClean(selectedWT.Path)
+	if err != nil {
+		return m, showError(fmt.Sprintf("Failed to check worktree status: %v", err))
+	}
+	if !clean {
+		return m, showError(fmt.Sprintf("'%s' has uncommitted changes - commit or stash them first", selectedWT.Name))
+	}
+
+	baseBranch, err := git.ResolveBaseBranch(repo.Path, repo.BaseBranch, repo.Type == "remote")
+	if err != nil {
+		return m, showError(fmt.Sprintf("Failed to determine base branch: %v", err))
+	}
+	if baseBranch == selectedWT.Branch {
+		return m, showError(fmt.Sprintf("'%s' is the base branch", selectedWT.Branch))
+	}
+
+	fetch := needsFetch(repo, baseBranch)
+	repoPath := repo.Path
+	strategy := syncStrategy(repo)
+	m.errorMsg = ""
+	m.successMsg = fmt.Sprintf("Syncing '%s' with %s...", selectedWT.Name, baseBranch)
+
+	return m, func() tea.Msg {
+		if fetch {
+			if err := git.Fetch(repoPath); err != nil {
+				return syncResultMsg{worktreeName: selectedWT.Name, worktreePath: selectedWT.Path, baseBranch: baseBranch, err: err}
+			}
+		}
+		err := git.SyncWithBase(selectedWT.Path, baseBranch, strategy)
+		return syncResultMsg{worktreeName: selectedWT.Name, worktreePath: selectedWT.Path, baseBranch: baseBranch, err: err}
+	}
+}
+
+// needsFetch reports whether syncing with baseBranch should fetch first, so that a
+// remote-tracking base like origin/main is up to date, also in local repositories
+func needsFetch(repo *config.Repository, baseBranch string) bool {
+	return repo.Type == "remote" || git.IsRemoteTrackingBranch(repo.Path, baseBranch)
+}
+
+// startBulkSync syncs all clean worktrees of the selected repository, aborting on conflicts
+func (m Model) startBulkSync() (tea.Model, tea.Cmd) {
+	repo := m.state.GetSelectedRepo()
+	baseBranch, err := git.ResolveBaseBranch(repo.Path, repo.BaseBranch, repo.Type == "remote")
+	if err != nil {
+		return m, showError(fmt.Sprintf("Failed to determine base branch: %v", err))
+	}
+
+	worktrees := append([]state.Worktree{}, m.state.Worktrees...)
+	fetch := needsFetch(repo, baseBranch)
+	repoPath := repo.Path
+	strategy := syncStrategy(repo)
+	m.errorMsg = ""
+	m.successMsg = fmt.Sprintf("Syncing all clean worktrees of '%s' with %s...", repo.Name, baseBranch)
+
+	return m, func() tea.Msg {
+		var result bulkSyncResultMsg
+		if fetch {
+			if err := git.Fetch(repoPath); err != nil {
+				result.failed = append(result.failed, fmt.Sprintf("fetch: %v", err))
+				return result
+			}
+		}
+
+		for _, wt := range worktrees {
+			if wt.Branch == "detached HEAD" || wt.Branch == baseBranch || git.SyncInProgress(wt.Path) != "" {
+				result.skipped = append(result.skipped, wt.Name)
+				continue
+			}
+			clean, err := git.IsClean(wt.Path)
+			if err != nil || !clean {
+				result.skipped = append(result.skipped, wt.Name)
+				continue
+			}
+
+			err = git.SyncWithBase(wt.Path, baseBranch, strategy)
+			if conflictErr, ok := git.IsConflict(err); ok {
+				_ = git.AbortSync(wt.Path, conflictErr.Operation)
+				result.failed = append(result.failed, fmt.Sprintf("%s (conflicts, aborted)", wt.Name))
+				continue
+			}
+			if err != nil {
+				result.failed = append(result.failed, wt.Name)
+				continue
+			}
+			result.updated = append(result.updated, wt.Name)
+		}
+		return result
+	}
+}
+
+// handleSyncResult shows the outcome of a sync and opens the conflict dialog if needed
+func (m Model) handleSyncResult(msg syncResultMsg) (tea.Model, tea.Cmd) {
+	m, loadCmd := m.refreshWorktree()
+	m.successMsg = ""
+
+	if conflictErr, ok := git.IsConflict(msg.err); ok {
+		m.syncConflictDialog = NewSyncConflictDialog(msg.worktreeName, msg.worktreePath, conflictErr.Operation, conflictErr.Files)
+		m.dialogType = DialogSyncConflict
+		m.errorMsg = ""
+		return m, loadCmd
+	}
+	if msg.err != nil {
+		return m, tea.Batch(loadCmd, showError(fmt.Sprintf("Failed to sync '%s': %v", msg.worktreeName, msg.err)))
+	}
+
+	m.errorMsg = ""
+	return m, tea.Batch(loadCmd, showSuccess(fmt.Sprintf("'%s' is up to date with %s", msg.worktreeName, msg.baseBranch)))
+}
+
+// handleBulkSyncResult reports the outcome of syncing all worktrees of a repository
+func (m Model) handleBulkSyncResult(msg bulkSyncResultMsg) (tea.Model, tea.Cmd) {
+	m, loadCmd := m.refreshWorktree()
+	m.successMsg = ""
+
+	summary := fmt.Sprintf("Updated %d, skipped %d (dirty, detached or base)", len(msg.updated), len(msg.skipped))
+	if len(msg.failed) > 0 {
+		return m, tea.Batch(loadCmd, showError(fmt.Sprintf("%s, failed %d: %s", summary, len(msg.failed), strings.Join(msg.failed, ", "))))
+	}
+
+	m.errorMsg = ""
+	return m, tea.Batch(loadCmd, showSuccess(summary))
+}
+
+func (m Model) handleSyncConflictKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	d := m.syncConflictDialog
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc", "q":
+		m.dialogType = DialogNone
+		m.errorMsg = ""
+		return m.refreshWorktree()
+
+	case "a":
+		if err := git.AbortSync(d.worktreePath, d.operation); err != nil {
+			m.errorMsg = err.Error()
+			return m, nil
+		}
+		m.dialogType = DialogNone
+		m.errorMsg = ""
+		m, loadCmd := m.refreshWorktree()
+		return m, tea.Batch(loadCmd, showSuccess(fmt.Sprintf("Aborted %s of '%s'", d.operation, d.worktreeName)))
+
+	case "c":
+		err := git.ContinueSync(d.worktreePath, d.operation)
+		if conflictErr, ok := git.IsConflict(err); ok {
+			m.syncConflictDialog.files = conflictErr.Files
+			m.errorMsg = ""
+			return m, nil
+		}
+		if err != nil {
+			m.errorMsg = err.Error()
+			return m, nil
+		}
+		m.dialogType = DialogNone
+		m.errorMsg = ""
+		m, loadCmd := m.refreshWorktree()
+		return m, tea.Batch(loadCmd, showSuccess(fmt.Sprintf("Finished %s of '%s'", d.operation, d.worktreeName)))
+	}
+
+	return m, nil
+}
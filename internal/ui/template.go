@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"path/filepath"
+
+	"github.com/michael-rose/workman/internal/config"
+	"github.com/michael-rose/workman/internal/git"
+	"github.com/michael-rose/workman/internal/state"
+	"github.com/michael-rose/workman/internal/template"
+)
+
+// templateVars returns the values of the variables a template uses for a worktree
+// Notes, HEAD and upstream are only looked up when the template refers to them
+func templateVars(t *template.Template, cfg *config.Config, repo *config.Repository, wt state.Worktree) template.Vars {
+	vars := template.Vars{
+		"root_dir":      cfg.RootDirectory,
+		"worktree_path": wt.Path,
+		"worktree_name": wt.Name,
+		"branch_name":   wt.Branch,
+	}
+	if repo != nil {
+		vars["repo_name"] = repo.Name
+		vars["repo_path"] = repo.Path
+		vars["repo_url"] = repo.URL
+		if t.Uses("notes") {
+			vars["notes"], _ = config.GetWorktreeNotes(repo.Name, wt.Name)
+		}
+	}
+	if t.Uses("head") {
+		vars["head"], _ = git.HeadCommit(wt.Path)
+	}
+	if t.Uses("upstream") {
+		vars["upstream"] = git.GetUpstream(wt.Path)
+	}
+	return vars
+}
+
+// renderTemplate expands a template for a worktree
+func renderTemplate(text string, cfg *config.Config, repo *config.Repository, wt state.Worktree) (string, error) {
+	t, err := template.Parse(text)
+	if err != nil {
+		return "", err
+	}
+	return t.Execute(templateVars(t, cfg, repo, wt)), nil
+}
+
+// newWorktreePath applies the worktree_path_template to a branch about to get a worktree
+func (m Model) newWorktreePath(repo *config.Repository, branch string) (string, error) {
+	text := m.state.Config.WorktreePathTemplate
+	if text == "" {
+		text = config.DefaultWorktreePath
+	}
+	path, err := renderTemplate(text, m.state.Config, repo, state.Worktree{Branch: branch})
+	if err != nil {
+		return "", err
+	}
+	if path, err = expandHome(path); err != nil {
+		return "", err
+	}
+	return filepath.Clean(path), nil
+}
@@ -0,0 +1,213 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme holds the colors of the user interface
+type Theme struct {
+	Primary    lipgloss.TerminalColor // headers, active borders and the selection
+	Muted      lipgloss.TerminalColor // secondary text like hints and labels
+	Border     lipgloss.TerminalColor // inactive borders and separators
+	SelectedBg lipgloss.TerminalColor // background of the selected item
+	Success    lipgloss.TerminalColor // success messages and added lines
+	SuccessBg  lipgloss.TerminalColor // background of the success banner
+	Error      lipgloss.TerminalColor // errors, warnings, removed lines and destructive dialogs
+	ErrorBg    lipgloss.TerminalColor // background of the error banner
+	Info       lipgloss.TerminalColor // diff hunk headers
+	Text       lipgloss.TerminalColor // notes
+	Faint      lipgloss.TerminalColor // placeholders like "no notes"
+	Backdrop   lipgloss.TerminalColor // pattern behind dialogs
+	Highlight  lipgloss.TerminalColor // text on the primary color, like the selected suggestion
+
+	// Monochrome marks the selection and active pane without colors
+	Monochrome bool
+}
+
+// themes are the built-in themes by name
+var themes = map[string]func() Theme{
+	"default":       defaultTheme,
+	"high-contrast": highContrastTheme,
+	"monochrome":    monochromeTheme,
+	"solarized":     solarizedTheme,
+}
+
+func defaultTheme() Theme {
+	return Theme{
+		Primary:    lipgloss.AdaptiveColor{Light: "#7C3AED", Dark: "#A78BFA"},
+		Muted:      lipgloss.AdaptiveColor{Light: "#6B7280", Dark: "#9CA3AF"},
+		Border:     lipgloss.AdaptiveColor{Light: "#D1D5DB", Dark: "#4B5563"},
+		SelectedBg: lipgloss.AdaptiveColor{Light: "#EDE9FE", Dark: "#1F2937"},
+		Success:    lipgloss.AdaptiveColor{Light: "#047857", Dark: "#10B981"},
+		SuccessBg:  lipgloss.AdaptiveColor{Light: "#D1FAE5", Dark: "#064E3B"},
+		Error:      lipgloss.AdaptiveColor{Light: "#B91C1C", Dark: "#EF4444"},
+		ErrorBg:    lipgloss.AdaptiveColor{Light: "#FEE2E2", Dark: "#7F1D1D"},
+		Info:       lipgloss.AdaptiveColor{Light: "#0369A1", Dark: "#38BDF8"},
+		Text:       lipgloss.AdaptiveColor{Light: "#4B5563", Dark: "#D1D5DB"},
+		Faint:      lipgloss.AdaptiveColor{Light: "#9CA3AF", Dark: "#6B7280"},
+		Backdrop:   lipgloss.AdaptiveColor{Light: "#E5E7EB", Dark: "#1F2937"},
+		Highlight:  lipgloss.AdaptiveColor{Light: "#1F2937", Dark: "#F9FAFB"},
+	}
+}
+
+func highContrastTheme() Theme {
+	return Theme{
+		Primary:    lipgloss.AdaptiveColor{Light: "#1E1B8A", Dark: "#FFD700"},
+		Muted:      lipgloss.AdaptiveColor{Light: "#1F2937", Dark: "#E5E7EB"},
+		Border:     lipgloss.AdaptiveColor{Light: "#000000", Dark: "#FFFFFF"},
+		SelectedBg: lipgloss.AdaptiveColor{Light: "#FDE68A", Dark: "#1E3A8A"},
+		Success:    lipgloss.AdaptiveColor{Light: "#005A00", Dark: "#00FF7F"},
+		SuccessBg:  lipgloss.AdaptiveColor{Light: "#DCFCE7", Dark: "#003300"},
+		Error:      lipgloss.AdaptiveColor{Light: "#A00000", Dark: "#FF5F5F"},
+		ErrorBg:    lipgloss.AdaptiveColor{Light: "#FFE4E6", Dark: "#4C0000"},
+		Info:       lipgloss.AdaptiveColor{Light: "#00008B", Dark: "#00FFFF"},
+		Text:       lipgloss.AdaptiveColor{Light: "#000000", Dark: "#FFFFFF"},
+		Faint:      lipgloss.AdaptiveColor{Light: "#374151", Dark: "#D1D5DB"},
+		Backdrop:   lipgloss.AdaptiveColor{Light: "#D1D5DB", Dark: "#374151"},
+		Highlight:  lipgloss.AdaptiveColor{Light: "#FFFFFF", Dark: "#000000"},
+	}
+}
+
+// monochromeTheme uses no colors at all, as asked for by NO_COLOR
+func monochromeTheme() Theme {
+	none := lipgloss.NoColor{}
+	return Theme{
+		Primary:    none,
+		Muted:      none,
+		Border:     none,
+		SelectedBg: none,
+		Success:    none,
+		SuccessBg:  none,
+		Error:      none,
+		ErrorBg:    none,
+		Info:       none,
+		Text:       none,
+		Faint:      none,
+		Backdrop:   none,
+		Highlight:  none,
+		Monochrome: true,
+	}
+}
+
+func solarizedTheme() Theme {
+	return Theme{
+		Primary:    lipgloss.AdaptiveColor{Light: "#6C71C4", Dark: "#268BD2"},
+		Muted:      lipgloss.AdaptiveColor{Light: "#657B83", Dark: "#839496"},
+		Border:     lipgloss.AdaptiveColor{Light: "#93A1A1", Dark: "#586E75"},
+		SelectedBg: lipgloss.AdaptiveColor{Light: "#EEE8D5", Dark: "#073642"},
+		Success:    lipgloss.Color("#859900"),
+		SuccessBg:  lipgloss.AdaptiveColor{Light: "#EEE8D5", Dark: "#073642"},
+		Error:      lipgloss.Color("#DC322F"),
+		ErrorBg:    lipgloss.AdaptiveColor{Light: "#EEE8D5", Dark: "#073642"},
+		Info:       lipgloss.Color("#2AA198"),
+		Text:       lipgloss.AdaptiveColor{Light: "#586E75", Dark: "#93A1A1"},
+		Faint:      lipgloss.AdaptiveColor{Light: "#93A1A1", Dark: "#586E75"},
+		Backdrop:   lipgloss.AdaptiveColor{Light: "#EEE8D5", Dark: "#073642"},
+		Highlight:  lipgloss.AdaptiveColor{Light: "#FDF6E3", Dark: "#002B36"},
+	}
+}
+
+// colors maps the names used in the [theme] table to the colors of a theme
+func (t *Theme) colors() map[string]*lipgloss.TerminalColor {
+	return map[string]*lipgloss.TerminalColor{
+		"primary":     &t.Primary,
+		"muted":       &t.Muted,
+		"border":      &t.Border,
+		"selected_bg": &t.SelectedBg,
+		"success":     &t.Success,
+		"success_bg":  &t.SuccessBg,
+		"error":       &t.Error,
+		"error_bg":    &t.ErrorBg,
+		"info":        &t.Info,
+		"text":        &t.Text,
+		"faint":       &t.Faint,
+		"backdrop":    &t.Backdrop,
+		"highlight":   &t.Highlight,
+	}
+}
+
+// NewTheme returns the theme selected by the "name" entry of the [theme] table with the other entries
+// overriding its colors. A non-empty NO_COLOR environment variable selects the monochrome theme
+func NewTheme(settings map[string]string) (Theme, error) {
+	name := settings["name"]
+	if name == "" {
+		name = "default"
+	}
+	newTheme, ok := themes[name]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme '%s' (available: %s)", name, strings.Join(themeNames(), ", "))
+	}
+	theme := newTheme()
+
+	colors := theme.colors()
+	for setting, value := range settings {
+		if setting == "name" {
+			continue
+		}
+		color, ok := colors[setting]
+		if !ok {
+			return Theme{}, fmt.Errorf("unknown theme color '%s'", setting)
+		}
+		parsed, err := parseColor(value)
+		if err != nil {
+			return Theme{}, fmt.Errorf("invalid theme color '%s': %w", setting, err)
+		}
+		*color = parsed
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return monochromeTheme(), nil
+	}
+	return theme, nil
+}
+
+func themeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// parseColor parses a color of the [theme] table: a hex color, an ANSI color number,
+// "none", or a light and a dark color separated by a comma
+func parseColor(value string) (lipgloss.TerminalColor, error) {
+	value = strings.TrimSpace(value)
+	if light, dark, ok := strings.Cut(value, ","); ok {
+		light, dark = strings.TrimSpace(light), strings.TrimSpace(dark)
+		if err := validateColor(light); err != nil {
+			return nil, err
+		}
+		if err := validateColor(dark); err != nil {
+			return nil, err
+		}
+		return lipgloss.AdaptiveColor{Light: light, Dark: dark}, nil
+	}
+	if value == "none" {
+		return lipgloss.NoColor{}, nil
+	}
+	if err := validateColor(value); err != nil {
+		return nil, err
+	}
+	return lipgloss.Color(value), nil
+}
+
+func validateColor(value string) error {
+	if hexColor.MatchString(value) {
+		return nil
+	}
+	if n, err := strconv.Atoi(value); err == nil && n >= 0 && n <= 255 {
+		return nil
+	}
+	return fmt.Errorf("'%s' is neither a hex color like #A78BFA nor an ANSI color number", value)
+}
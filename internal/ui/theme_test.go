@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestNewTheme(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]string
+		wantErr  string
+	}{
+		{name: "default"},
+		{name: "built-in", settings: map[string]string{"name": "solarized"}},
+		{name: "override", settings: map[string]string{"name": "high-contrast", "primary": "#FF0000", "muted": "8"}},
+		{name: "unknown theme", settings: map[string]string{"name": "neon"}, wantErr: "unknown theme 'neon'"},
+		{name: "unknown color", settings: map[string]string{"accent": "#FF0000"}, wantErr: "unknown theme color 'accent'"},
+		{name: "invalid color", settings: map[string]string{"primary": "purple"}, wantErr: "invalid theme color 'primary'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", "")
+			_, err := NewTheme(tt.settings)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewTheme_Overrides(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	theme, err := NewTheme(map[string]string{"primary": "#FF0000", "border": "#000000, #FFFFFF", "backdrop": "none"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if theme.Primary != lipgloss.Color("#FF0000") {
+		t.Errorf("primary = %v, want #FF0000", theme.Primary)
+	}
+	if theme.Border != (lipgloss.AdaptiveColor{Light: "#000000", Dark: "#FFFFFF"}) {
+		t.Errorf("border = %v, want adaptive #000000/#FFFFFF", theme.Border)
+	}
+	if theme.Backdrop != (lipgloss.NoColor{}) {
+		t.Errorf("backdrop = %v, want no color", theme.Backdrop)
+	}
+	if theme.Muted != defaultTheme().Muted {
+		t.Errorf("muted = %v, want the default", theme.Muted)
+	}
+}
+
+func TestNewTheme_NoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	theme, err := NewTheme(map[string]string{"name": "solarized", "primary": "#FF0000"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !theme.Monochrome || theme.Primary != (lipgloss.NoColor{}) {
+		t.Errorf("NO_COLOR did not select the monochrome theme: %+v", theme)
+	}
+}
@@ -0,0 +1,302 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/michael-rose/workman/internal/git"
+	"github.com/michael-rose/workman/internal/state"
+)
+
+// skippedDirs are not watched inside worktrees, just like directories ignored by git
+var skippedDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+}
+
+// metaFiles are the entries that matter in git directories; others like the index change on every git status
+var metaFiles = map[string]bool{
+	"HEAD":        true,
+	"packed-refs": true,
+	"worktrees":   true,
+}
+
+// Event lists what changed since the last event
+type Event struct {
+	Repos     []string // names of repositories whose worktrees or branches changed
+	Worktrees []string // paths of worktrees with changed files
+}
+
+// target describes what a watched directory belongs to
+type target struct {
+	repo      string
+	worktree  string // empty for git directories
+	meta      bool   // only metaFiles are relevant
+	recursive bool   // new subdirectories are watched as well
+}
+
+// Watcher watches the git directories and worktrees of repositories and reports changes after a debounce
+type Watcher struct {
+	fs       *fsnotify.Watcher
+	debounce time.Duration
+	maxDirs  int
+	events   chan Event
+	done     chan struct{} // closed by Close
+	once     sync.Once
+
+	watchMu   sync.Mutex // serializes Watch calls without blocking event handling
+	mu        sync.Mutex
+	dirs      map[string]target
+	counts    map[string]int      // watched directories by repository
+	worktrees map[string][]string // watched worktree paths by repository
+	repos     map[string]bool     // pending repository changes
+	changed   map[string]bool     // pending worktree changes
+	timer     *time.Timer
+}
+
+// New starts a watcher that watches at most maxDirs directories per repository
+func New(debounce time.Duration, maxDirs int) (*Watcher, error) {
+	fs, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		fs:        fs,
+		debounce:  debounce,
+		maxDirs:   maxDirs,
+		events:    make(chan Event, 16),
+		done:      make(chan struct{}),
+		dirs:      map[string]target{},
+		counts:    map[string]int{},
+		worktrees: map[string][]string{},
+		repos:     map[string]bool{},
+		changed:   map[string]bool{},
+	}
+	go w.loop()
+	return w, nil
+}
+
+// Events returns the channel debounced change events are sent to
+func (w *Watcher) Events() <-chan Event {
+	return w.events
+}
+
+// Close stops watching; pending changes are dropped
+func (w *Watcher) Close() error {
+	var err error
+	w.once.Do(func() {
+		close(w.done)
+		err = w.fs.Close()
+
+		w.mu.Lock()
+		if w.timer != nil {
+			w.timer.Stop()
+		}
+		w.mu.Unlock()
+	})
+	return err
+}
+
+// Watch watches the branches and worktrees of a repository, replacing previous watches of it
+// Does nothing if the worktrees are unchanged since the last call
+func (w *Watcher) Watch(repoName, repoPath string, worktrees []state.Worktree) {
+	paths := make([]string, len(worktrees))
+	for i, wt := range worktrees {
+		paths[i] = wt.Path
+	}
+
+	w.watchMu.Lock()
+	defer w.watchMu.Unlock()
+
+	w.mu.Lock()
+	current, ok := w.worktrees[repoName]
+	w.mu.Unlock()
+	if ok && slices.Equal(current, paths) {
+		return
+	}
+
+	// Directories are collected before taking the lock so events keep flowing during the walk
+	ignored := map[string]bool{}
+	for _, path := range paths {
+		dirs, _ := git.IgnoredDirs(path)
+		for _, dir := range dirs {
+			ignored[dir] = true
+		}
+	}
+	var dirs []watchDir
+	gitDir := filepath.Join(repoPath, ".git")
+	if info, err := os.Stat(gitDir); err == nil && info.IsDir() {
+		dirs = append(dirs, watchDir{gitDir, target{repo: repoName, meta: true}})
+		dirs = w.collectTree(dirs, filepath.Join(gitDir, "refs", "heads"), target{repo: repoName, recursive: true}, nil)
+
+		// Worktrees are registered here; each one has its own HEAD
+		worktreesDir := filepath.Join(gitDir, "worktrees")
+		dirs = append(dirs, watchDir{worktreesDir, target{repo: repoName}})
+		entries, _ := os.ReadDir(worktreesDir)
+		for _, entry := range entries {
+			if entry.IsDir() {
+				dirs = append(dirs, watchDir{filepath.Join(worktreesDir, entry.Name()), target{repo: repoName, meta: true}})
+			}
+		}
+	}
+	for _, path := range paths {
+		dirs = w.collectTree(dirs, path, target{repo: repoName, worktree: path, recursive: true}, ignored)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.worktrees[repoName] = paths
+	for dir, t := range w.dirs {
+		if t.repo == repoName {
+			_ = w.fs.Remove(dir)
+			delete(w.dirs, dir)
+		}
+	}
+	delete(w.counts, repoName)
+	w.addAll(dirs)
+}
+
+// watchDir is a directory to watch
+type watchDir struct {
+	path string
+	t    target
+}
+
+// collectTree appends a directory and its subdirectories, up to the limit, to dirs
+func (w *Watcher) collectTree(dirs []watchDir, root string, t target, ignored map[string]bool) []watchDir {
+	_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if path != root && (skippedDirs[d.Name()] || ignored[path]) {
+			return filepath.SkipDir
+		}
+		if len(dirs) >= w.maxDirs {
+			return filepath.SkipAll
+		}
+		dirs = append(dirs, watchDir{path, t})
+		return nil
+	})
+	return dirs
+}
+
+// addAll watches directories until the limit is reached; the caller holds the lock
+func (w *Watcher) addAll(dirs []watchDir) {
+	for _, dir := range dirs {
+		if !w.add(dir.path, dir.t) {
+			return
+		}
+	}
+}
+
+// add watches a single directory if the limit of its repository allows it; the caller holds the lock
+func (w *Watcher) add(dir string, t target) bool {
+	if _, ok := w.dirs[dir]; ok {
+		return true
+	}
+	if w.counts[t.repo] >= w.maxDirs {
+		return false
+	}
+	if err := w.fs.Add(dir); err != nil {
+		return true
+	}
+	w.dirs[dir] = t
+	w.counts[t.repo]++
+	return true
+}
+
+func (w *Watcher) loop() {
+	for {
+		select {
+		case event, ok := <-w.fs.Events:
+			if !ok {
+				return
+			}
+			w.handle(event)
+		case _, ok := <-w.fs.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// handle records a filesystem event and schedules a change event
+func (w *Watcher) handle(event fsnotify.Event) {
+	if event.Op == fsnotify.Chmod || strings.HasSuffix(event.Name, ".lock") {
+		return
+	}
+
+	w.mu.Lock()
+	// Removed directories are no longer watched
+	if removed, ok := w.dirs[event.Name]; ok && (event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename)) {
+		delete(w.dirs, event.Name)
+		w.counts[removed.repo]--
+	}
+	t, ok := w.dirs[filepath.Dir(event.Name)]
+	w.mu.Unlock()
+
+	if !ok {
+		return
+	}
+	if t.meta && !metaFiles[filepath.Base(event.Name)] {
+		return
+	}
+
+	// Watch directories created inside worktrees and branch namespaces; ignored ones, like build
+	// output, are neither watched nor reported
+	var dirs []watchDir
+	if event.Has(fsnotify.Create) && t.recursive {
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() && !skippedDirs[info.Name()] {
+			if t.worktree != "" && git.IsIgnored(t.worktree, event.Name) {
+				return
+			}
+			dirs = w.collectTree(nil, event.Name, t, nil)
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.addAll(dirs)
+	if t.worktree != "" {
+		w.changed[t.worktree] = true
+	} else {
+		w.repos[t.repo] = true
+	}
+
+	// Events are collected for one debounce interval after the first one
+	if w.timer == nil {
+		w.timer = time.AfterFunc(w.debounce, w.flush)
+	}
+}
+
+// flush sends the collected changes as one event
+func (w *Watcher) flush() {
+	w.mu.Lock()
+	var event Event
+	for repo := range w.repos {
+		event.Repos = append(event.Repos, repo)
+	}
+	for path := range w.changed {
+		event.Worktrees = append(event.Worktrees, path)
+	}
+	w.repos = map[string]bool{}
+	w.changed = map[string]bool{}
+	w.timer = nil
+	w.mu.Unlock()
+
+	slices.Sort(event.Repos)
+	slices.Sort(event.Worktrees)
+	select {
+	case w.events <- event:
+	case <-w.done:
+	}
+}
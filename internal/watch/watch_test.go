@@ -0,0 +1,165 @@
+package watch
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/michael-rose/workman/internal/state"
+)
+
+func TestWatcherEvents(t *testing.T) {
+	root := t.TempDir()
+	repoPath := filepath.Join(root, "app")
+	worktreePath := filepath.Join(root, "app-feature")
+	for _, dir := range []string{
+		filepath.Join(repoPath, ".git", "refs", "heads"),
+		filepath.Join(repoPath, ".git", "worktrees", "app-feature"),
+		filepath.Join(worktreePath, "src"),
+	} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w, err := New(50*time.Millisecond, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = w.Close() }()
+	w.Watch("app", repoPath, []state.Worktree{{Name: "app", Path: repoPath}, {Name: "app-feature", Path: worktreePath}})
+
+	write := func(path string) {
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Changes to the index are ignored, file changes are reported once per debounce interval
+	write(filepath.Join(repoPath, ".git", "worktrees", "app-feature", "index"))
+	write(filepath.Join(worktreePath, "src", "main.go"))
+	write(filepath.Join(worktreePath, "README.md"))
+	event := receive(t, w)
+	if len(event.Repos) != 0 || len(event.Worktrees) != 1 || event.Worktrees[0] != worktreePath {
+		t.Errorf("Expected change of the feature worktree only, got %+v", event)
+	}
+
+	// New branches are reported as repository changes
+	write(filepath.Join(repoPath, ".git", "refs", "heads", "topic"))
+	event = receive(t, w)
+	if len(event.Repos) != 1 || event.Repos[0] != "app" || len(event.Worktrees) != 0 {
+		t.Errorf("Expected change of repository app, got %+v", event)
+	}
+}
+
+func TestWatcherLimit(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"a", "b", "c", "d"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w, err := New(50*time.Millisecond, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = w.Close() }()
+	w.Watch("app", filepath.Join(root, "missing"), []state.Worktree{{Name: "app", Path: root}})
+
+	if len(w.dirs) != 3 {
+		t.Errorf("Expected 3 watched directories, got %d", len(w.dirs))
+	}
+
+	// Each repository has its own limit
+	other := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(other, "a"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	w.Watch("other", filepath.Join(other, "missing"), []state.Worktree{{Name: "other", Path: other}})
+	if len(w.dirs) != 5 {
+		t.Errorf("Expected 5 watched directories, got %d", len(w.dirs))
+	}
+}
+
+func TestWatcherSkipsIgnored(t *testing.T) {
+	root := t.TempDir()
+	if output, err := exec.Command("git", "init", "-q", root).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, output)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".gitignore"), []byte("target/\ndist/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{"target/debug", "src"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w, err := New(50*time.Millisecond, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = w.Close() }()
+	w.Watch("app", root, []state.Worktree{{Name: "app", Path: root}})
+
+	// Build output created later is not watched either
+	if err := os.Mkdir(filepath.Join(root, "dist"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "src", "main.go"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	receive(t, w)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.dirs[filepath.Join(root, "src")]; !ok {
+		t.Error("Expected src to be watched")
+	}
+	for _, dir := range []string{"target", "target/debug", "dist"} {
+		if _, ok := w.dirs[filepath.Join(root, dir)]; ok {
+			t.Errorf("Expected ignored %s not to be watched", dir)
+		}
+	}
+}
+
+func TestWatcherClose(t *testing.T) {
+	w, err := New(50*time.Millisecond, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("Expected second Close to succeed, got %v", err)
+	}
+
+	// Nobody reads events after Close, so flushing must not block once the buffer is full
+	done := make(chan struct{})
+	go func() {
+		for range cap(w.events) + 1 {
+			w.flush()
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("flush blocked after Close")
+	}
+}
+
+func receive(t *testing.T, w *Watcher) Event {
+	t.Helper()
+	select {
+	case event := <-w.Events():
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for event")
+		return Event{}
+	}
+}
@@ -18,17 +18,43 @@ func main() {
 		os.Exit(1)
 	}
 
+	keys, err := ui.NewKeyMap(cfg.Keys)
+	if err != nil {
+		fmt.Printf("Error loading config: invalid [keys] table: %v\n", err)
+		os.Exit(1)
+	}
+	if err := ui.ValidateActionKeys(keys, cfg); err != nil {
+		fmt.Printf("Error loading config: invalid [[actions]]: %v\n", err)
+		os.Exit(1)
+	}
+
+	theme, err := ui.NewTheme(cfg.Theme)
+	if err != nil {
+		fmt.Printf("Error loading config: invalid [theme] table: %v\n", err)
+		os.Exit(1)
+	}
+	ui.ApplyTheme(theme)
+
 	// Initialize application state
 	appState := state.New(cfg)
 
 	// Create the Bubble Tea model
-	model := ui.NewModel(appState)
+	model := ui.NewModel(appState, keys)
 
 	// Create the Bubble Tea program
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	options := []tea.ProgramOption{tea.WithAltScreen()}
+	if cfg.Mouse {
+		options = append(options, tea.WithMouseCellMotion())
+	}
+	p := tea.NewProgram(model, options...)
 
-	// Run the program
-	if _, err := p.Run(); err != nil {
+	// Run the program; the final model holds the running post-create script, if any
+	final, err := p.Run()
+	if m, ok := final.(ui.Model); ok {
+		model = m
+	}
+	model.Close()
+	if err != nil {
 		fmt.Printf("Error running program: %v\n", err)
 		os.Exit(1)
 	}